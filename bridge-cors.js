@@ -0,0 +1,59 @@
+/**
+ * CORS handling for the per-epoch bridge HTTP server.
+ *
+ * Mirrors the Go cors package used by serve-dashboard.go: when ALLOWED_ORIGINS
+ * is set, only listed origins receive CORS headers. Entries may be exact origins
+ * ("https://app.example.com") or wildcard subdomains ("https://*.example.com");
+ * unset allows any origin for local development.
+ */
+
+// Parse the comma-separated ALLOWED_ORIGINS value, ignoring empty entries
+function parseAllowedOrigins(value) {
+    return (value || '')
+        .split(',')
+        .map(origin => origin.trim())
+        .filter(origin => origin !== '');
+}
+
+// Return the Access-Control-Allow-Origin value for the request origin, or '' if not allowed
+function matchOrigin(allowed, origin) {
+    if (allowed.length === 0) {
+        return '*';
+    }
+    for (const entry of allowed) {
+        if (entry === '*') {
+            return '*';
+        }
+        if (!origin) {
+            continue;
+        }
+        if (entry === origin) {
+            return origin;
+        }
+        const i = entry.indexOf('*.');
+        if (i >= 0) {
+            const scheme = entry.substring(0, i);
+            const suffix = entry.substring(i + 1);
+            if (origin.startsWith(scheme) && origin.endsWith(suffix) && origin.length > scheme.length + suffix.length) {
+                return origin;
+            }
+        }
+    }
+    return '';
+}
+
+// Set CORS headers on the response if the request origin is allowed
+function applyCors(allowed, req, res) {
+    const allowOrigin = matchOrigin(allowed, req.headers.origin);
+    if (allowOrigin === '') {
+        return;
+    }
+    res.setHeader('Access-Control-Allow-Origin', allowOrigin);
+    if (allowOrigin !== '*') {
+        res.setHeader('Vary', 'Origin');
+    }
+    res.setHeader('Access-Control-Allow-Methods', 'GET, POST, OPTIONS');
+    res.setHeader('Access-Control-Allow-Headers', 'Content-Type, X-Epoch-Signature');
+}
+
+module.exports = { parseAllowedOrigins, matchOrigin, applyCors };
@@ -0,0 +1,75 @@
+package consensus
+
+import "math"
+
+// DefaultThreshold is the BFT majority a side's weight must exceed to decide the outcome
+const DefaultThreshold = 0.5
+
+// epsilon absorbs floating point error when summing fractional validator weights
+const epsilon = 1e-9
+
+// Decision is the outcome of a weighted vote
+type Decision string
+
+const (
+	Pending  Decision = "pending"   // Neither side has exceeded the threshold yet
+	Accepted Decision = "accepted"  // Accept weight exceeded the threshold
+	Rejected Decision = "rejected"  // Reject weight exceeded the threshold
+	Tied     Decision = "tied"      // Both sides hold exactly the threshold weight
+	NoQuorum Decision = "no-quorum" // Too little weight voted for any outcome to count
+)
+
+// Tally accumulates weighted accept/reject votes and the quality scores behind them
+type Tally struct {
+	TotalWeight   float64 // Sum of all validator weights that have voted
+	AcceptVotes   float64 // Sum of weights from validators who accepted
+	RejectVotes   float64 // Sum of weights from validators who rejected
+	AcceptQuality float64 // Weight-scaled quality scores of accepting validators
+	RejectQuality float64 // Weight-scaled quality scores of rejecting validators
+	VoteCount     int     // Total number of votes received
+}
+
+// Add records one validator's weighted vote and quality score
+func (t *Tally) Add(weight float64, accept bool, quality float64) {
+	t.TotalWeight += weight
+	t.VoteCount++
+
+	if accept {
+		t.AcceptVotes += weight
+		t.AcceptQuality += weight * quality
+	} else {
+		t.RejectVotes += weight
+		t.RejectQuality += weight * quality
+	}
+}
+
+// Result decides the vote. The outcome requires at least quorum weight to have
+// voted; a side then wins by holding more than threshold weight. If both sides
+// hold exactly threshold weight (a 50/50 split at the default), the vote is Tied.
+func (t *Tally) Result(threshold, quorum float64) Decision {
+	if t.TotalWeight+epsilon < quorum {
+		return NoQuorum
+	}
+
+	accepted := t.AcceptVotes > threshold
+	rejected := t.RejectVotes > threshold
+	switch {
+	case accepted && rejected:
+		// Only possible with thresholds below one half; the heavier side wins
+		if math.Abs(t.AcceptVotes-t.RejectVotes) < epsilon {
+			return Tied
+		}
+		if t.AcceptVotes > t.RejectVotes {
+			return Accepted
+		}
+		return Rejected
+	case accepted:
+		return Accepted
+	case rejected:
+		return Rejected
+	case math.Abs(t.AcceptVotes-threshold) < epsilon && math.Abs(t.RejectVotes-threshold) < epsilon:
+		return Tied
+	default:
+		return Pending
+	}
+}
@@ -0,0 +1,91 @@
+package consensus
+
+import "testing"
+
+// tallyOf builds a tally from accept and reject weights, one vote each
+func tallyOf(accept, reject []float64) *Tally {
+	var t Tally
+	for _, weight := range accept {
+		t.Add(weight, true, 0.8)
+	}
+	for _, weight := range reject {
+		t.Add(weight, false, 0.2)
+	}
+	return &t
+}
+
+func TestResultDefaultThreshold(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept []float64
+		reject []float64
+		want   Decision
+	}{
+		{"no votes", nil, nil, Pending},
+		{"accept majority", []float64{0.25, 0.25, 0.25}, []float64{0.25}, Accepted},
+		{"reject majority", []float64{0.25}, []float64{0.25, 0.25, 0.25}, Rejected},
+		{"exactly half accepts so far", []float64{0.25, 0.25}, nil, Pending},
+		{"exact tie", []float64{0.25, 0.25}, []float64{0.25, 0.25}, Tied},
+		{"tie of thirds and sixths", []float64{1.0 / 3, 1.0 / 6}, []float64{1.0 / 6, 1.0 / 3}, Tied},
+		{"just over half", []float64{0.5000001}, []float64{0.4999999}, Accepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tallyOf(tt.accept, tt.reject).Result(DefaultThreshold, 0); got != tt.want {
+				t.Errorf("Result() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultQuorum(t *testing.T) {
+	tally := tallyOf([]float64{0.25, 0.25}, []float64{0.1})
+
+	if got := tally.Result(DefaultThreshold, 0.7); got != NoQuorum {
+		t.Errorf("Result() with 0.6 of 0.7 quorum = %s, want %s", got, NoQuorum)
+	}
+	if got := tally.Result(DefaultThreshold, 0.6); got != Pending {
+		t.Errorf("Result() at exactly the quorum = %s, want %s", got, Pending)
+	}
+	tally.Add(0.4, true, 0.9)
+	if got := tally.Result(DefaultThreshold, 0.6); got != Accepted {
+		t.Errorf("Result() after quorum and majority = %s, want %s", got, Accepted)
+	}
+}
+
+func TestResultLowThreshold(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept []float64
+		reject []float64
+		want   Decision
+	}{
+		{"heavier accept side wins", []float64{0.4}, []float64{0.35}, Accepted},
+		{"heavier reject side wins", []float64{0.35}, []float64{0.4}, Rejected},
+		{"equal sides over threshold tie", []float64{0.4}, []float64{0.4}, Tied},
+		{"neither side over threshold", []float64{0.2}, []float64{0.2}, Pending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tallyOf(tt.accept, tt.reject).Result(0.3, 0); got != tt.want {
+				t.Errorf("Result(0.3, 0) = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddAccumulatesWeightedQuality(t *testing.T) {
+	var tally Tally
+	tally.Add(0.5, true, 0.8)
+	tally.Add(0.25, false, 0.4)
+	tally.Add(0.25, true, 0.6)
+
+	if tally.VoteCount != 3 || tally.TotalWeight != 1 || tally.AcceptVotes != 0.75 || tally.RejectVotes != 0.25 {
+		t.Errorf("tally = %+v", tally)
+	}
+	if tally.AcceptQuality != 0.55 || tally.RejectQuality != 0.1 {
+		t.Errorf("AcceptQuality/RejectQuality = %v/%v, want 0.55/0.1", tally.AcceptQuality, tally.RejectQuality)
+	}
+}
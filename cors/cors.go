@@ -0,0 +1,60 @@
+// Package cors implements the ALLOWED_ORIGINS allowlist shared by the dashboard
+// server and the per-epoch bridge (bridge-cors.js mirrors it in JavaScript).
+package cors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ParseAllowedOrigins reads a comma-separated origin allowlist. An empty list
+// or an explicit "*" allows any origin.
+func ParseAllowedOrigins(value string) []string {
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// MatchOrigin reports the Access-Control-Allow-Origin value to send for the request
+// origin, or "" if the origin is not allowed. Entries may be exact origins
+// ("https://app.example.com") or wildcard subdomains ("https://*.example.com").
+func MatchOrigin(allowed []string, origin string) string {
+	if len(allowed) == 0 {
+		return "*"
+	}
+	for _, entry := range allowed {
+		if entry == "*" {
+			return "*"
+		}
+		if origin == "" {
+			continue
+		}
+		if entry == origin {
+			return origin
+		}
+		if i := strings.Index(entry, "*."); i >= 0 {
+			scheme, suffix := entry[:i], entry[i+1:]
+			if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, suffix) && len(origin) > len(scheme)+len(suffix) {
+				return origin
+			}
+		}
+	}
+	return ""
+}
+
+// SetAllowOrigin sets Access-Control-Allow-Origin if the request origin is allowed,
+// adding Vary: Origin when the response depends on it
+func SetAllowOrigin(w http.ResponseWriter, r *http.Request, allowed []string) {
+	allowOrigin := MatchOrigin(allowed, r.Header.Get("Origin"))
+	if allowOrigin == "" {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+	if allowOrigin != "*" {
+		w.Header().Add("Vary", "Origin")
+	}
+}
@@ -0,0 +1,80 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// The vectors below match tests/test-bridge-cors.js so both implementations agree
+
+func TestParseAllowedOrigins(t *testing.T) {
+	got := ParseAllowedOrigins(" https://a.example.com, ,https://*.example.org ")
+	want := []string{"https://a.example.com", "https://*.example.org"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAllowedOrigins() = %q, want %q", got, want)
+	}
+	if got := ParseAllowedOrigins(""); len(got) != 0 {
+		t.Errorf("ParseAllowedOrigins(\"\") = %q, want none", got)
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.example.org"}
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    string
+	}{
+		{"empty allowlist", nil, "https://evil.test", "*"},
+		{"explicit wildcard", []string{"*"}, "https://evil.test", "*"},
+		{"exact", allowed, "https://app.example.com", "https://app.example.com"},
+		{"wildcard subdomain", allowed, "https://dash.example.org", "https://dash.example.org"},
+		{"wildcard needs a subdomain", allowed, "https://example.org", ""},
+		{"wildcard keeps the scheme", allowed, "http://dash.example.org", ""},
+		{"unlisted", allowed, "https://evil.test", ""},
+		{"suffix lookalike", allowed, "https://evilexample.org", ""},
+		{"no origin", allowed, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchOrigin(tt.allowed, tt.origin); got != tt.want {
+				t.Errorf("MatchOrigin(%q) = %q, want %q", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAllowOrigin(t *testing.T) {
+	allowed := []string{"https://app.example.com"}
+	tests := []struct {
+		name      string
+		allowed   []string
+		origin    string
+		wantAllow string
+		wantVary  string
+	}{
+		{"allowed origin", allowed, "https://app.example.com", "https://app.example.com", "Origin"},
+		{"rejected origin", allowed, "https://evil.test", "", ""},
+		{"open allowlist", nil, "https://anything.test", "*", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/contract_addresses.json", nil)
+			r.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			SetAllowOrigin(w, r, tt.allowed)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if got := w.Header().Get("Vary"); got != tt.wantVary {
+				t.Errorf("Vary = %q, want %q", got, tt.wantVary)
+			}
+		})
+	}
+}
@@ -2,26 +2,103 @@ package dgraph
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"log"
+	"math/rand"
+	"sync"
+	"time"
 
 	"github.com/dgraph-io/dgo/v210"
 	"github.com/dgraph-io/dgo/v210/protos/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// Dg is the global Dgraph client instance
-var Dg *dgo.Dgraph
+var (
+	clientMu sync.RWMutex     // Guards dg and conn, which InitDgraphTLS and Close replace
+	dg       *dgo.Dgraph      // Global Dgraph client instance (nil = not connected)
+	conn     *grpc.ClientConn // gRPC connection behind dg
+)
+
+// schemaRetries is how many times setting the schema is attempted while Dgraph starts up
+const schemaRetries = 5
+
+// reconnectBackoff controls how the connection redials after Dgraph becomes unreachable
+var reconnectBackoff = backoff.Config{
+	BaseDelay:  500 * time.Millisecond,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   15 * time.Second,
+}
+
+// Client returns the global Dgraph client, or nil if Dgraph is not connected
+func Client() *dgo.Dgraph {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return dg
+}
+
+// setClient replaces the global client and connection, closing the previous connection
+func setClient(client *dgo.Dgraph, clientConn *grpc.ClientConn) {
+	clientMu.Lock()
+	previous := conn
+	dg, conn = client, clientConn
+	clientMu.Unlock()
+
+	if previous != nil && previous != clientConn {
+		previous.Close()
+	}
+}
 
-// InitDgraph initializes the connection to Dgraph
-func InitDgraph(address string) {
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// backoffDelay returns how long to wait before retry number retries (counting from 0),
+// growing by reconnectBackoff's Multiplier up to MaxDelay with Jitter applied, the same
+// way gRPC spaces redials
+func backoffDelay(retries int) time.Duration {
+	delay, maxDelay := float64(reconnectBackoff.BaseDelay), float64(reconnectBackoff.MaxDelay)
+	for ; delay < maxDelay && retries > 0; retries-- {
+		delay *= reconnectBackoff.Multiplier
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	delay *= 1 + reconnectBackoff.Jitter*(rand.Float64()*2-1)
+	if delay < 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// InitDgraph initializes an insecure connection to Dgraph
+func InitDgraph(address string) error {
+	return InitDgraphTLS(address, nil)
+}
+
+// InitDgraphTLS initializes the connection to Dgraph, using TLS when tlsConfig is set.
+// The connection redials with exponential backoff whenever Dgraph becomes unreachable,
+// so a transient outage does not permanently break the client.
+// The new client replaces any previous one, whose connection is closed.
+// If the schema cannot be set, the connection is closed and Client returns nil.
+func InitDgraphTLS(address string, tlsConfig *tls.Config) error {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	clientConn, err := grpc.Dial(address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           reconnectBackoff,
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	)
 	if err != nil {
-		log.Fatalf("Failed to connect to Dgraph: %v", err)
+		return fmt.Errorf("failed to connect to Dgraph: %v", err)
 	}
 
-	dc := api.NewDgraphClient(conn)
-	Dg = dgo.NewDgraphClient(dc)
+	client := dgo.NewDgraphClient(api.NewDgraphClient(clientConn))
 
 	op := &api.Operation{
 		Schema: `
@@ -32,7 +109,7 @@ func InitDgraph(address string) {
 			value: string .
 			key: string .
 			node: string .
-			parent: [uid] .
+			parent: [uid] @reverse .
 			type Event {
 				id
 				name
@@ -46,9 +123,86 @@ func InitDgraph(address string) {
 		`,
 	}
 
-	if err := Dg.Alter(context.Background(), op); err != nil {
-		log.Fatalf("Failed to set schema: %v", err)
+	for attempt := 1; ; attempt++ {
+		err = client.Alter(context.Background(), op)
+		if err == nil {
+			break
+		}
+		if attempt == schemaRetries {
+			clientConn.Close()
+			setClient(nil, nil)
+			return fmt.Errorf("failed to set schema: %v", err)
+		}
+
+		delay := backoffDelay(attempt - 1)
+		log.Printf("Failed to set schema (attempt %d/%d), retrying in %v: %v", attempt, schemaRetries, delay, err)
+		time.Sleep(delay)
 	}
 
+	setClient(client, clientConn)
 	log.Println("Connected to Dgraph and schema set successfully")
+	return nil
+}
+
+// Close closes the Dgraph connection, if any, so Client returns nil
+func Close() {
+	setClient(nil, nil)
+}
+
+// Ping checks that Dgraph is reachable by running a minimal read-only query
+func Ping(ctx context.Context) error {
+	client := Client()
+	if client == nil {
+		return fmt.Errorf("dgraph client not initialized")
+	}
+
+	txn := client.NewReadOnlyTxn()
+	defer txn.Discard(ctx)
+
+	if _, err := txn.Query(ctx, `schema(pred: [id]) { type }`); err != nil {
+		return fmt.Errorf("dgraph ping failed: %v", err)
+	}
+	return nil
+}
+
+// StartHealthCheck pings Dgraph every interval, logging when it becomes unavailable
+// and when it recovers. While Dgraph is down, each failed ping also asks the
+// connection to redial rather than waiting for the next commit to do so.
+func StartHealthCheck(interval time.Duration) chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		healthy := true
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				err := Ping(ctx)
+				cancel()
+
+				switch {
+				case err != nil && healthy:
+					log.Printf("Dgraph unavailable, reconnecting: %v", err)
+				case err == nil && !healthy:
+					log.Println("Dgraph connection restored")
+				}
+				healthy = err == nil
+				if !healthy {
+					clientMu.RLock()
+					clientConn := conn
+					clientMu.RUnlock()
+					if clientConn != nil {
+						clientConn.Connect()
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return done
 }
@@ -0,0 +1,189 @@
+package dgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph/dgraphtest"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connectFakeDgraph starts a fake Dgraph and points the package client at it
+func connectFakeDgraph(t *testing.T) *dgraphtest.Fake {
+	t.Helper()
+	fake := dgraphtest.Start(t)
+	if err := InitDgraph(fake.Address()); err != nil {
+		t.Fatalf("InitDgraph() error = %v", err)
+	}
+	t.Cleanup(Close)
+	return fake
+}
+
+// fastReconnects shortens redial and schema retry delays for the rest of the test
+func fastReconnects(t *testing.T) {
+	t.Helper()
+	backoffConfig, retryInterval := reconnectBackoff, commitRetryInterval
+	reconnectBackoff.BaseDelay = time.Millisecond
+	reconnectBackoff.MaxDelay = 10 * time.Millisecond
+	commitRetryInterval = time.Millisecond
+	t.Cleanup(func() { reconnectBackoff, commitRetryInterval = backoffConfig, retryInterval })
+}
+
+// pingWithin pings Dgraph with a short timeout
+func pingWithin(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return Ping(ctx)
+}
+
+func TestInitDgraphSetsSchemaAndPings(t *testing.T) {
+	fake := connectFakeDgraph(t)
+
+	if got := fake.AlterCount(); got != 1 {
+		t.Errorf("schema alterations = %d, want 1", got)
+	}
+	if err := pingWithin(time.Second); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestPingWithoutClient(t *testing.T) {
+	Close()
+	if err := pingWithin(time.Second); err == nil {
+		t.Error("Ping() succeeded without an initialized client")
+	}
+}
+
+func TestInitDgraphRetriesSchema(t *testing.T) {
+	fastReconnects(t)
+	fake := dgraphtest.Start(t)
+	fake.OnAlter = func(n int) error {
+		if n < 3 {
+			return status.Error(codes.Unavailable, "starting up")
+		}
+		return nil
+	}
+
+	if err := InitDgraph(fake.Address()); err != nil {
+		t.Fatalf("InitDgraph() error = %v", err)
+	}
+	t.Cleanup(Close)
+	if got := fake.AlterCount(); got != 3 {
+		t.Errorf("schema alterations = %d, want 3", got)
+	}
+}
+
+func TestInitDgraphReturnsSchemaFailure(t *testing.T) {
+	fastReconnects(t)
+	fake := dgraphtest.Start(t)
+	fake.OnAlter = func(n int) error {
+		return status.Error(codes.PermissionDenied, "schema locked")
+	}
+
+	err := InitDgraph(fake.Address())
+	t.Cleanup(Close)
+	if err == nil || !strings.Contains(err.Error(), "failed to set schema") {
+		t.Fatalf("InitDgraph() error = %v, want a schema failure", err)
+	}
+	if got := fake.AlterCount(); got != schemaRetries {
+		t.Errorf("schema alterations = %d, want %d", got, schemaRetries)
+	}
+	if Client() != nil {
+		t.Error("failed InitDgraph() left a client behind")
+	}
+	if err := NewEventGraph(1, "node-1").CommitToGraph(); err != nil {
+		t.Errorf("CommitToGraph() with no pending events error = %v", err)
+	}
+}
+
+func TestClientRecoversAfterDgraphRestart(t *testing.T) {
+	fastReconnects(t)
+	fake := connectFakeDgraph(t)
+	eg := NewEventGraph(1, "node-1")
+	first := eg.AddEvent("UserInput", "input", "task", map[int]int{2: 1}, nil)
+
+	fake.Stop()
+	if err := pingWithin(200 * time.Millisecond); err == nil {
+		t.Fatal("Ping() succeeded while Dgraph was down")
+	}
+	if err := eg.CommitToGraph(); err == nil {
+		t.Fatal("CommitToGraph() succeeded while Dgraph was down")
+	}
+	if got := eg.PendingCount(); got != 1 {
+		t.Fatalf("PendingCount() = %d after a failed commit, want 1", got)
+	}
+
+	fake.Restart(t)
+	deadline := time.Now().Add(5 * time.Second)
+	for pingWithin(200*time.Millisecond) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not reconnect after Dgraph restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("CommitToGraph() after restart error = %v", err)
+	}
+	if events := fake.Committed(); len(events) != 1 || events[0].ID != first {
+		t.Errorf("committed events = %v, want only %s", events, first)
+	}
+}
+
+func TestBackoffDelayFollowsReconnectBackoff(t *testing.T) {
+	defer func(config backoff.Config) { reconnectBackoff = config }(reconnectBackoff)
+	reconnectBackoff = backoff.Config{BaseDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: time.Second}
+
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for retries, delay := range want {
+		if got := backoffDelay(retries); got != delay*time.Millisecond {
+			t.Errorf("backoffDelay(%d) = %v, want %v", retries, got, delay*time.Millisecond)
+		}
+	}
+
+	reconnectBackoff.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := backoffDelay(10); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("backoffDelay(10) = %v, want MaxDelay within 20%% jitter", got)
+		}
+	}
+}
+
+func TestClientReplacedWhileInUse(t *testing.T) {
+	fastReconnects(t)
+	fake := connectFakeDgraph(t)
+
+	// Commits and pings run while the client is closed and reconnected; under
+	// -race this checks every reader goes through Client
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			eg := NewEventGraph(1, fmt.Sprintf("node-%d", worker))
+			for i := 0; i < 20; i++ {
+				eg.AddEvent("Step", "step", "value", map[int]int{1: i + 1}, nil)
+				eg.CommitToGraph()
+				pingWithin(100 * time.Millisecond)
+			}
+		}(worker)
+	}
+
+	for i := 0; i < 5; i++ {
+		Close()
+		if err := InitDgraph(fake.Address()); err != nil {
+			t.Errorf("InitDgraph() error = %v", err)
+		}
+	}
+	wg.Wait()
+
+	if err := pingWithin(time.Second); err != nil {
+		t.Errorf("Ping() after reconnecting error = %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Package dgraphtest provides an in-memory Dgraph server for tests.
+package dgraphtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v210/protos/api"
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fake is an in-memory Dgraph gRPC server that stores committed events and
+// answers the queries the dgraph package issues: mutations, the ping query and
+// recursive causal queries
+type Fake struct {
+	api.UnimplementedDgraphServer
+
+	mu        sync.Mutex
+	events    map[string]models.Event // Committed events keyed by UID
+	order     []string                // UIDs in commit order
+	nextUID   int
+	mutations int // Mutation attempts received, including failed ones
+	alters    int // Schema alterations received, including failed ones
+	server    *grpc.Server
+	address   string
+
+	// OnMutation, if set, runs before each mutation attempt n (counting from 1) is
+	// applied; a non-nil error fails the attempt without storing anything
+	OnMutation func(n int) error
+	// OnAlter, if set, decides the result of schema alteration n (counting from 1)
+	OnAlter func(n int) error
+}
+
+// Start serves a fake Dgraph on a local port until the test ends
+func Start(t *testing.T) *Fake {
+	t.Helper()
+	fake := &Fake{events: make(map[string]models.Event)}
+	fake.listen(t, "127.0.0.1:0")
+	return fake
+}
+
+// Address returns the host:port the fake is served on
+func (f *Fake) Address() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.address
+}
+
+// listen serves the fake on address until the test ends or stop is called
+func (f *Fake) listen(t *testing.T, address string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	api.RegisterDgraphServer(server, f)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	f.mu.Lock()
+	f.server, f.address = server, listener.Addr().String()
+	f.mu.Unlock()
+}
+
+// Stop drops every connection and stops serving, simulating a Dgraph outage
+func (f *Fake) Stop() {
+	f.mu.Lock()
+	server := f.server
+	f.mu.Unlock()
+	server.Stop()
+}
+
+// Restart serves the fake again on its previous address, keeping committed events
+func (f *Fake) Restart(t *testing.T) {
+	t.Helper()
+	f.listen(t, f.Address())
+}
+
+func (f *Fake) Alter(ctx context.Context, op *api.Operation) (*api.Payload, error) {
+	f.mu.Lock()
+	f.alters++
+	attempt, hook := f.alters, f.OnAlter
+	f.mu.Unlock()
+
+	if hook != nil {
+		if err := hook(attempt); err != nil {
+			return nil, err
+		}
+	}
+	return &api.Payload{}, nil
+}
+
+func (f *Fake) CommitOrAbort(ctx context.Context, txn *api.TxnContext) (*api.TxnContext, error) {
+	return txn, nil
+}
+
+func (f *Fake) Query(ctx context.Context, req *api.Request) (*api.Response, error) {
+	if len(req.Mutations) > 0 {
+		return f.mutate(req.Mutations)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if strings.Contains(req.Query, "@recurse") {
+		return f.causalQuery(req)
+	}
+	return &api.Response{Json: []byte(`{}`)}, nil
+}
+
+// mutate stores the events of each mutation, assigning UIDs to blank nodes
+func (f *Fake) mutate(mutations []*api.Mutation) (*api.Response, error) {
+	f.mu.Lock()
+	f.mutations++
+	attempt, hook := f.mutations, f.OnMutation
+	f.mu.Unlock()
+
+	// The hook runs unlocked so it may add events or query the fake
+	if hook != nil {
+		if err := hook(attempt); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	uids := make(map[string]string)
+	assign := func(uid string) string {
+		if !strings.HasPrefix(uid, "_:") {
+			return uid
+		}
+		blank := strings.TrimPrefix(uid, "_:")
+		if _, ok := uids[blank]; !ok {
+			f.nextUID++
+			uids[blank] = fmt.Sprintf("0x%x", f.nextUID)
+		}
+		return uids[blank]
+	}
+
+	for _, mutation := range mutations {
+		var events []models.Event
+		if err := json.Unmarshal(mutation.SetJson, &events); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		for _, event := range events {
+			event.UID = assign(event.UID)
+			for i, parent := range event.Parent {
+				if strings.HasPrefix(parent.UID, "_:") {
+					if _, ok := uids[strings.TrimPrefix(parent.UID, "_:")]; !ok {
+						return nil, status.Error(codes.InvalidArgument, "unresolved blank node "+parent.UID)
+					}
+				} else if _, ok := f.events[parent.UID]; !ok {
+					return nil, status.Error(codes.InvalidArgument, "unknown parent uid "+parent.UID)
+				}
+				event.Parent[i] = models.ParentRef{UID: assign(parent.UID)}
+			}
+			f.events[event.UID] = event
+			f.order = append(f.order, event.UID)
+		}
+	}
+	return &api.Response{Uids: uids}, nil
+}
+
+var recurseDepthPattern = regexp.MustCompile(`@recurse\(depth: (\d+)`)
+
+// causalQuery answers a recursive parent or ~parent query the way Dgraph does with
+// loop: false: edges are followed up to depth levels, expanding each node at most once
+func (f *Fake) causalQuery(req *api.Request) (*api.Response, error) {
+	match := recurseDepthPattern.FindStringSubmatch(req.Query)
+	if match == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing recurse depth")
+	}
+	depth, _ := strconv.Atoi(match[1])
+	edge := "parent"
+	if strings.Contains(req.Query, "~parent") {
+		edge = "~parent"
+	}
+
+	neighbours := func(uid string) []string {
+		if edge == "parent" {
+			var parents []string
+			for _, parent := range f.events[uid].Parent {
+				parents = append(parents, parent.UID)
+			}
+			return parents
+		}
+		var children []string
+		for _, childUID := range f.order {
+			for _, parent := range f.events[childUID].Parent {
+				if parent.UID == uid {
+					children = append(children, childUID)
+				}
+			}
+		}
+		return children
+	}
+
+	visited := make(map[string]bool)
+	var expand func(uid string, level int) map[string]interface{}
+	expand = func(uid string, level int) map[string]interface{} {
+		visited[uid] = true
+		event := f.events[uid]
+		node := map[string]interface{}{
+			"uid": event.UID, "id": event.ID, "name": event.Name, "clock": event.Clock,
+			"depth": event.Depth, "value": event.Value, "key": event.Key, "node": event.Node,
+		}
+		if level >= depth {
+			return node
+		}
+		var next []interface{}
+		for _, neighbour := range neighbours(uid) {
+			if !visited[neighbour] {
+				next = append(next, expand(neighbour, level+1))
+			}
+		}
+		if len(next) > 0 {
+			node[edge] = next
+		}
+		return node
+	}
+
+	roots := make([]interface{}, 0)
+	for _, uid := range f.order {
+		if f.events[uid].ID == req.Vars["$id"] {
+			roots = append(roots, expand(uid, 1))
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"events": roots})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.Response{Json: data}, nil
+}
+
+// Committed returns the committed events in commit order
+func (f *Fake) Committed() []models.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events := make([]models.Event, 0, len(f.order))
+	for _, uid := range f.order {
+		events = append(events, f.events[uid])
+	}
+	return events
+}
+
+// AlterCount returns how many schema alterations the fake has received
+func (f *Fake) AlterCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.alters
+}
+
+// MutationCount returns how many mutation attempts the fake has received
+func (f *Fake) MutationCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mutations
+}
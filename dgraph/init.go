@@ -3,24 +3,42 @@ package dgraph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/dgraph-io/dgo/v210"
 	"github.com/dgraph-io/dgo/v210/protos/api"
 	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// DefaultCommitRetries is how many times a commit chunk is retried after a transient Dgraph error
+const DefaultCommitRetries = 3
+
+// commitRetryInterval is the base backoff between commit retries, doubled on each attempt
+var commitRetryInterval = 500 * time.Millisecond
+
+// ErrPendingEventLimit is returned by TryAddEvent when MaxPendingEvents uncommitted events are held
+var ErrPendingEventLimit = errors.New("pending event limit reached")
+
 // EventGraph represents the causal event graph
 type EventGraph struct {
-	Events   []models.Event
-	UIDMap   map[string]string
-	EventMu  sync.RWMutex
-	Depth    int
-	NodeID   int
-	NodeAddr string
+	Events           []models.Event
+	UIDMap           map[string]string
+	EventMu          sync.RWMutex
+	Depth            int
+	NodeID           int
+	NodeAddr         string
+	MaxPendingEvents int // Maximum uncommitted events accepted by TryAddEvent (0 = unlimited)
+	CommitBatchSize  int // Maximum events per Dgraph mutation in CommitToGraph (0 = all in one)
+	CommitRetries    int // Retries per mutation after transient Dgraph errors
+
+	commitMu sync.Mutex // Serializes commits with each other and with LoadEvents/RestoreState
 }
 
 // NewEventGraph creates a new event graph instance
@@ -31,6 +49,8 @@ func NewEventGraph(nodeID int, nodeAddr string) *EventGraph {
 		Depth:    0,
 		NodeID:   nodeID,
 		NodeAddr: nodeAddr,
+
+		CommitRetries: DefaultCommitRetries,
 	}
 }
 
@@ -45,6 +65,30 @@ func (eg *EventGraph) AddEvent(name string, key string, value string, clock map[
 	eg.EventMu.Lock()
 	defer eg.EventMu.Unlock()
 
+	return eg.addEventLocked(name, key, value, clock, parentIDs)
+}
+
+// TryAddEvent adds a new event unless MaxPendingEvents uncommitted events are
+// already held, in which case it returns ErrPendingEventLimit
+func (eg *EventGraph) TryAddEvent(name string, key string, value string, clock map[int]int, parentIDs []string) (string, error) {
+	eg.EventMu.Lock()
+	defer eg.EventMu.Unlock()
+
+	if eg.MaxPendingEvents > 0 && len(eg.Events) >= eg.MaxPendingEvents {
+		return "", fmt.Errorf("%w (%d uncommitted events)", ErrPendingEventLimit, len(eg.Events))
+	}
+	return eg.addEventLocked(name, key, value, clock, parentIDs), nil
+}
+
+// PendingCount returns the number of events not yet committed to Dgraph
+func (eg *EventGraph) PendingCount() int {
+	eg.EventMu.RLock()
+	defer eg.EventMu.RUnlock()
+	return len(eg.Events)
+}
+
+// addEventLocked adds a new event; the caller must hold EventMu
+func (eg *EventGraph) addEventLocked(name string, key string, value string, clock map[int]int, parentIDs []string) string {
 	eg.Depth++
 	eventID := fmt.Sprintf("e%d_%d", eg.NodeID, eg.Depth)
 
@@ -82,21 +126,86 @@ func (eg *EventGraph) AddEvent(name string, key string, value string, clock map[
 	return eventID
 }
 
-// CommitToGraph commits all pending events to Dgraph
+// CommitToGraph commits all pending events to Dgraph.
+//
+// Events are committed in insertion order, which is causal order, in mutations of
+// at most CommitBatchSize events. After each mutation the assigned UIDs replace the
+// blank nodes that later events use as parent references, so parents resolve across
+// chunks. If a chunk fails, the events already committed are dropped from the pending
+// list and the rest are kept for the next commit.
+//
+// EventMu is released while a mutation is in flight, so events can still be added
+// during a slow commit. Only the events pending when the commit started are committed.
 func (eg *EventGraph) CommitToGraph() error {
-	eg.EventMu.Lock()
-	defer eg.EventMu.Unlock()
+	eg.commitMu.Lock()
+	defer eg.commitMu.Unlock()
 
-	if len(eg.Events) == 0 {
+	eg.EventMu.RLock()
+	total := len(eg.Events)
+	batchSize := eg.CommitBatchSize
+	eg.EventMu.RUnlock()
+
+	if total == 0 {
 		return nil
 	}
+	client := Client()
+	if client == nil {
+		return fmt.Errorf("dgraph client not initialized")
+	}
 
-	mutationJSON, err := json.Marshal(eg.Events)
+	for committed := 0; committed < total; {
+		size := total - committed
+		if batchSize > 0 && size > batchSize {
+			size = batchSize
+		}
+
+		// Events are only appended while commitMu is held, so the chunk stays at the front
+		eg.EventMu.RLock()
+		chunk := make([]models.Event, size)
+		copy(chunk, eg.Events[:size])
+		eg.EventMu.RUnlock()
+
+		uids, err := eg.commitChunk(client, chunk)
+		if err != nil {
+			return fmt.Errorf("%v (%d of %d events committed)", err, committed, total)
+		}
+
+		eg.EventMu.Lock()
+		eg.Events = eg.Events[size:]
+		eg.resolveBlankNodes(uids)
+		eg.EventMu.Unlock()
+		committed += size
+	}
+
+	log.Println("Chrono event graph committed to Dgraph")
+	return nil
+}
+
+// commitChunk commits one mutation, retrying with exponential backoff on transient errors
+func (eg *EventGraph) commitChunk(client *dgo.Dgraph, events []models.Event) (map[string]string, error) {
+	mutationJSON, err := json.Marshal(events)
 	if err != nil {
-		return fmt.Errorf("failed to marshal events: %v", err)
+		return nil, fmt.Errorf("failed to marshal events: %v", err)
 	}
 
-	txn := Dg.NewTxn()
+	for attempt := 0; ; attempt++ {
+		uids, err := mutateEvents(client, mutationJSON)
+		if err == nil {
+			return uids, nil
+		}
+		if attempt >= eg.CommitRetries || !isTransientDgraphError(err) {
+			return nil, fmt.Errorf("failed to commit events to Dgraph: %v", err)
+		}
+
+		backoff := commitRetryInterval << attempt
+		log.Printf("Transient Dgraph commit error, retrying in %v: %v", backoff, err)
+		time.Sleep(backoff)
+	}
+}
+
+// mutateEvents runs a single committed mutation and returns the UIDs assigned to blank nodes
+func mutateEvents(client *dgo.Dgraph, mutationJSON []byte) (map[string]string, error) {
+	txn := client.NewTxn()
 	defer txn.Discard(context.Background())
 
 	mu := &api.Mutation{
@@ -104,14 +213,125 @@ func (eg *EventGraph) CommitToGraph() error {
 		CommitNow: true,
 	}
 
-	if _, err := txn.Mutate(context.Background(), mu); err != nil {
-		return fmt.Errorf("failed to commit events to Dgraph: %v", err)
+	resp, err := txn.Mutate(context.Background(), mu)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Uids, nil
+}
+
+// isTransientDgraphError reports whether a failed mutation is safe to retry: an aborted
+// transaction or an unavailable server. Timeouts are not retried because the mutation
+// may have been applied, and retrying would duplicate the events.
+func isTransientDgraphError(err error) bool {
+	if errors.Is(err, dgo.ErrAborted) {
+		return true
 	}
+	return status.Code(err) == codes.Unavailable
+}
 
-	eg.Events = make([]models.Event, 0)
+// resolveBlankNodes points blank node references at their assigned UIDs. Blank nodes
+// only resolve within one mutation, so committed events must be referenced by UID from
+// pending events and from events added later. The caller must hold EventMu.
+func (eg *EventGraph) resolveBlankNodes(uids map[string]string) {
+	resolve := func(uid string) string {
+		if !strings.HasPrefix(uid, "_:") {
+			return uid
+		}
+		if assigned, ok := uids[strings.TrimPrefix(uid, "_:")]; ok {
+			return assigned
+		}
+		return uid
+	}
 
-	log.Println("Chrono event graph committed to Dgraph")
-	return nil
+	for id, uid := range eg.UIDMap {
+		eg.UIDMap[id] = resolve(uid)
+	}
+	for i := range eg.Events {
+		// Replace rather than modify the parent slice, which copies returned by PendingEvents share
+		parents := make([]models.ParentRef, len(eg.Events[i].Parent))
+		for j, parent := range eg.Events[i].Parent {
+			parents[j] = models.ParentRef{UID: resolve(parent.UID)}
+		}
+		if len(parents) > 0 {
+			eg.Events[i].Parent = parents
+		}
+	}
+}
+
+// PendingEvents returns a copy of the events not yet committed to Dgraph
+func (eg *EventGraph) PendingEvents() []models.Event {
+	eg.EventMu.RLock()
+	defer eg.EventMu.RUnlock()
+
+	events := make([]models.Event, len(eg.Events))
+	copy(events, eg.Events)
+	return events
+}
+
+// LoadEvents replaces the pending events with the given ones and rebuilds the
+// ID-to-UID mapping from them alone, so newly added events can link to the loaded
+// events as parents but not to events that were pending or committed before
+func (eg *EventGraph) LoadEvents(events []models.Event) {
+	eg.commitMu.Lock()
+	defer eg.commitMu.Unlock()
+	eg.EventMu.Lock()
+	defer eg.EventMu.Unlock()
+
+	eg.Events = make([]models.Event, 0, len(events))
+	eg.UIDMap = make(map[string]string, len(events))
+	eg.Depth = 0
+	for _, event := range events {
+		eg.Events = append(eg.Events, event)
+		if event.ID != "" {
+			eg.UIDMap[event.ID] = event.UID
+		}
+		if event.Depth > eg.Depth {
+			eg.Depth = event.Depth
+		}
+	}
+}
+
+// EventGraphState is the serializable state of an EventGraph: its pending events, the
+// ID-to-UID mapping (including committed events) and the event depth counter
+type EventGraphState struct {
+	Events []models.Event    `json:"events"`
+	UIDMap map[string]string `json:"uidMap"`
+	Depth  int               `json:"depth"`
+}
+
+// ExportState returns a copy of the graph state so it can be persisted and restored
+func (eg *EventGraph) ExportState() EventGraphState {
+	eg.EventMu.RLock()
+	defer eg.EventMu.RUnlock()
+
+	state := EventGraphState{
+		Events: make([]models.Event, len(eg.Events)),
+		UIDMap: make(map[string]string, len(eg.UIDMap)),
+		Depth:  eg.Depth,
+	}
+	copy(state.Events, eg.Events)
+	for id, uid := range eg.UIDMap {
+		state.UIDMap[id] = uid
+	}
+	return state
+}
+
+// RestoreState replaces the graph state with a previously exported one, so new
+// events continue the event ID sequence and can link to earlier events as parents
+func (eg *EventGraph) RestoreState(state EventGraphState) {
+	eg.commitMu.Lock()
+	defer eg.commitMu.Unlock()
+	eg.EventMu.Lock()
+	defer eg.EventMu.Unlock()
+
+	eg.Events = make([]models.Event, len(state.Events))
+	copy(eg.Events, state.Events)
+	eg.UIDMap = make(map[string]string, len(state.UIDMap))
+	for id, uid := range state.UIDMap {
+		eg.UIDMap[id] = uid
+	}
+	eg.Depth = state.Depth
 }
 
 // StartAutoCommit starts automatic periodic commits to Dgraph
@@ -0,0 +1,181 @@
+package dgraph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph/dgraphtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// addChain adds count events to eg, each a child of the previous one and, every few
+// events, also of an event several places back so parents span commit chunks
+func addChain(eg *EventGraph, count int) []string {
+	var ids []string
+	for i := 0; i < count; i++ {
+		var parents []string
+		if i > 0 {
+			parents = append(parents, ids[i-1])
+		}
+		if i >= 10 && i%5 == 0 {
+			parents = append(parents, ids[i-10])
+		}
+		ids = append(ids, eg.AddEvent("Step", fmt.Sprintf("step_%d", i), "value", map[int]int{1: i + 1}, parents))
+	}
+	return ids
+}
+
+// checkCommittedChain verifies the fake holds the events of addChain in order with
+// every parent reference pointing at a committed event
+func checkCommittedChain(t *testing.T, fake *dgraphtest.Fake, ids []string) {
+	t.Helper()
+	events := fake.Committed()
+	if len(events) != len(ids) {
+		t.Fatalf("committed %d events, want %d", len(events), len(ids))
+	}
+
+	uidByID := make(map[string]string, len(events))
+	for i, event := range events {
+		if event.ID != ids[i] {
+			t.Fatalf("event %d committed as %s, want %s", i, event.ID, ids[i])
+		}
+		uidByID[event.ID] = event.UID
+	}
+	for i, event := range events {
+		want := 0
+		if i > 0 {
+			want++
+		}
+		if i >= 10 && i%5 == 0 {
+			want++
+		}
+		if len(event.Parent) != want {
+			t.Errorf("event %s has %d parents, want %d", event.ID, len(event.Parent), want)
+			continue
+		}
+		if i > 0 && event.Parent[0].UID != uidByID[ids[i-1]] {
+			t.Errorf("event %s parent = %s, want %s", event.ID, event.Parent[0].UID, uidByID[ids[i-1]])
+		}
+	}
+}
+
+func TestCommitToGraphInChunks(t *testing.T) {
+	fake := connectFakeDgraph(t)
+	eg := NewEventGraph(1, "node-1")
+	eg.CommitBatchSize = 7
+	ids := addChain(eg, 250)
+
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("CommitToGraph() error = %v", err)
+	}
+
+	checkCommittedChain(t, fake, ids)
+	if got, want := fake.MutationCount(), (250+6)/7; got != want {
+		t.Errorf("mutations = %d, want %d", got, want)
+	}
+	if got := eg.PendingCount(); got != 0 {
+		t.Errorf("PendingCount() = %d, want 0", got)
+	}
+	for id, uid := range eg.UIDMap {
+		if strings.HasPrefix(uid, "_:") {
+			t.Errorf("UIDMap[%s] = %s, want an assigned UID", id, uid)
+		}
+	}
+}
+
+func TestCommitToGraphRetriesTransientError(t *testing.T) {
+	defer func(interval time.Duration) { commitRetryInterval = interval }(commitRetryInterval)
+	commitRetryInterval = time.Millisecond
+
+	fake := connectFakeDgraph(t)
+	fake.OnMutation = func(n int) error {
+		if n == 2 {
+			return status.Error(codes.Unavailable, "server restarting")
+		}
+		return nil
+	}
+	eg := NewEventGraph(1, "node-1")
+	eg.CommitBatchSize = 4
+	ids := addChain(eg, 12)
+
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("CommitToGraph() error = %v", err)
+	}
+	checkCommittedChain(t, fake, ids)
+	if got := fake.MutationCount(); got != 4 {
+		t.Errorf("mutations = %d, want 3 chunks plus 1 retry", got)
+	}
+}
+
+func TestCommitToGraphKeepsUncommittedChunksOnFailure(t *testing.T) {
+	fake := connectFakeDgraph(t)
+	fake.OnMutation = func(n int) error {
+		if n == 3 {
+			return status.Error(codes.InvalidArgument, "rejected")
+		}
+		return nil
+	}
+	eg := NewEventGraph(1, "node-1")
+	eg.CommitBatchSize = 5
+	ids := addChain(eg, 22)
+
+	err := eg.CommitToGraph()
+	if err == nil || !strings.Contains(err.Error(), "10 of 22 events committed") {
+		t.Fatalf("CommitToGraph() error = %v, want a failure after 10 events", err)
+	}
+	if got := len(fake.Committed()); got != 10 {
+		t.Errorf("committed %d events, want 10", got)
+	}
+	pending := eg.PendingEvents()
+	if len(pending) != 12 || pending[0].ID != ids[10] {
+		t.Fatalf("pending events = %d starting at %s, want 12 starting at %s", len(pending), pending[0].ID, ids[10])
+	}
+	for _, parent := range pending[0].Parent {
+		if strings.HasPrefix(parent.UID, "_:") {
+			t.Errorf("pending event %s still references committed parent by blank node %s", pending[0].ID, parent.UID)
+		}
+	}
+
+	// Committing again picks up where the failed commit stopped
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("second CommitToGraph() error = %v", err)
+	}
+	checkCommittedChain(t, fake, ids)
+}
+
+func TestCommitToGraphKeepsEventsAddedDuringCommit(t *testing.T) {
+	fake := connectFakeDgraph(t)
+	eg := NewEventGraph(1, "node-1")
+	eg.CommitBatchSize = 3
+	ids := addChain(eg, 6)
+
+	var lateID string
+	fake.OnMutation = func(n int) error {
+		if n == 1 {
+			lateID = eg.AddEvent("Late", "late", "added mid-commit", map[int]int{1: 7}, []string{ids[5]})
+		}
+		return nil
+	}
+
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("CommitToGraph() error = %v", err)
+	}
+	checkCommittedChain(t, fake, ids)
+	pending := eg.PendingEvents()
+	if len(pending) != 1 || pending[0].ID != lateID {
+		t.Fatalf("pending events = %v, want only %s", pending, lateID)
+	}
+
+	fake.OnMutation = nil
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("second CommitToGraph() error = %v", err)
+	}
+	events := fake.Committed()
+	last := events[len(events)-1]
+	if last.ID != lateID || len(last.Parent) != 1 || last.Parent[0].UID != events[5].UID {
+		t.Errorf("late event committed as %s with parents %v, want %s under %s", last.ID, last.Parent, lateID, events[5].UID)
+	}
+}
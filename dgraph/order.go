@@ -0,0 +1,137 @@
+package dgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+// ErrCausalCycle is returned by TopologicalOrder when parent links and VLC clocks
+// cannot be satisfied by any ordering of the events
+var ErrCausalCycle = errors.New("causal cycle detected")
+
+// TopologicalOrder returns the pending events in causal order: every event comes
+// after its parents and after every event whose VLC clock happens before its own.
+// Concurrent events are ordered by name, then key, then ID, so the result is
+// deterministic. Every pair of clocks is compared, so the cost grows quadratically
+// with the number of pending events.
+func (eg *EventGraph) TopologicalOrder() ([]models.Event, error) {
+	eg.EventMu.RLock()
+	events := make([]models.Event, len(eg.Events))
+	copy(events, eg.Events)
+	eg.EventMu.RUnlock()
+
+	return topologicalSort(events)
+}
+
+// topologicalSort orders events with Kahn's algorithm over parent edges and VLC
+// happens-before edges, always emitting the lowest ready event next
+func topologicalSort(events []models.Event) ([]models.Event, error) {
+	clocks := make([]*vlc.Clock, len(events))
+	indexByUID := make(map[string]int, len(events))
+	for i, event := range events {
+		clock, err := parseEventClock(event.Clock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock on event %s: %v", event.ID, err)
+		}
+		clocks[i] = clock
+		indexByUID[event.UID] = i
+	}
+
+	successors := make([][]int, len(events))
+	inDegree := make([]int, len(events))
+	addEdge := func(from, to int) {
+		successors[from] = append(successors[from], to)
+		inDegree[to]++
+	}
+
+	for i, event := range events {
+		// Parents already committed to Dgraph are not pending and impose no order here
+		for _, parent := range event.Parent {
+			if p, ok := indexByUID[parent.UID]; ok {
+				addEdge(p, i)
+			}
+		}
+		for j := i + 1; j < len(events); j++ {
+			switch clocks[i].Compare(clocks[j]) {
+			case vlc.Less:
+				addEdge(i, j)
+			case vlc.Greater:
+				addEdge(j, i)
+			}
+		}
+	}
+
+	ready := make([]int, 0)
+	for i := range events {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]models.Event, 0, len(events))
+	for len(ready) > 0 {
+		next := 0
+		for k := 1; k < len(ready); k++ {
+			if eventSortsBefore(events[ready[k]], events[ready[next]]) {
+				next = k
+			}
+		}
+		i := ready[next]
+		ready = append(ready[:next], ready[next+1:]...)
+
+		ordered = append(ordered, events[i])
+		for _, s := range successors[i] {
+			inDegree[s]--
+			if inDegree[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+
+	if len(ordered) < len(events) {
+		cyclic := make([]string, 0)
+		for i, event := range events {
+			if inDegree[i] > 0 {
+				cyclic = append(cyclic, event.ID)
+			}
+		}
+		return nil, fmt.Errorf("%w among events %s", ErrCausalCycle, strings.Join(cyclic, ", "))
+	}
+	return ordered, nil
+}
+
+// eventSortsBefore breaks ties between concurrent events by name, key and ID
+func eventSortsBefore(a, b models.Event) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	if a.Key != b.Key {
+		return a.Key < b.Key
+	}
+	return a.ID < b.ID
+}
+
+// parseEventClock decodes a clock stored by VectorClockToString
+func parseEventClock(clockJSON string) (*vlc.Clock, error) {
+	clock := vlc.New()
+	if clockJSON == "" {
+		return clock, nil
+	}
+
+	var values map[int]int
+	if err := json.Unmarshal([]byte(clockJSON), &values); err != nil {
+		return nil, err
+	}
+	for id, value := range values {
+		if id < 0 || value < 0 {
+			return nil, fmt.Errorf("negative clock entry %d:%d", id, value)
+		}
+		clock.Values[uint64(id)] = uint64(value)
+	}
+	return clock, nil
+}
@@ -0,0 +1,109 @@
+package dgraph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+// orderEvent builds a pending event whose UID is derived from its ID
+func orderEvent(id, name, clock string, parents ...string) models.Event {
+	event := models.Event{UID: "_:" + id, ID: id, Name: name, Key: id, Clock: clock}
+	for _, parent := range parents {
+		event.Parent = append(event.Parent, models.ParentRef{UID: "_:" + parent})
+	}
+	return event
+}
+
+func TestTopologicalOrderKnownDAG(t *testing.T) {
+	// genesis → miner, validator (concurrent) → merge → report, where report is only
+	// linked to validator but its clock also follows miner
+	events := []models.Event{
+		orderEvent("final", "RoundSuccess", `{"1":3,"2":2}`, "merge", "report"),
+		orderEvent("report", "ValidatorVote", `{"1":1,"2":2}`, "validator"),
+		orderEvent("merge", "MinerOutput", `{"1":2,"2":1}`, "miner", "validator"),
+		orderEvent("validator", "InfoRequest", `{"2":1}`, "genesis"),
+		orderEvent("miner", "MinerOutput", `{"1":1}`, "genesis"),
+		orderEvent("genesis", "Genesis", `{}`),
+	}
+
+	ordered, err := topologicalSort(events)
+	if err != nil {
+		t.Fatalf("topologicalSort() error = %v", err)
+	}
+
+	var ids []string
+	position := make(map[string]int)
+	for i, event := range ordered {
+		ids = append(ids, event.ID)
+		position[event.UID] = i
+	}
+	// Concurrent miner and validator events are ordered by name
+	if got, want := strings.Join(ids, ","), "genesis,validator,miner,merge,report,final"; got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+
+	for _, event := range ordered {
+		for _, parent := range event.Parent {
+			if position[parent.UID] >= position[event.UID] {
+				t.Errorf("%s ordered before its parent %s", event.ID, parent.UID)
+			}
+		}
+	}
+	for i := range ordered {
+		for j := i + 1; j < len(ordered); j++ {
+			a, _ := parseEventClock(ordered[i].Clock)
+			b, _ := parseEventClock(ordered[j].Clock)
+			if a.Compare(b) == vlc.Greater {
+				t.Errorf("%s %s ordered before %s %s", ordered[i].ID, a, ordered[j].ID, b)
+			}
+		}
+	}
+}
+
+func TestTopologicalOrderDetectsCycle(t *testing.T) {
+	// The parent link puts late before early, but the clocks put early first
+	events := []models.Event{
+		orderEvent("early", "UserInput", `{"1":1}`, "late"),
+		orderEvent("late", "MinerOutput", `{"1":2}`),
+		orderEvent("unrelated", "Genesis", `{}`),
+	}
+
+	_, err := topologicalSort(events)
+	if !errors.Is(err, ErrCausalCycle) {
+		t.Fatalf("topologicalSort() error = %v, want ErrCausalCycle", err)
+	}
+	if !strings.Contains(err.Error(), "early") || !strings.Contains(err.Error(), "late") {
+		t.Errorf("error %q does not name the cyclic events", err)
+	}
+}
+
+func TestTopologicalOrderRejectsInvalidClock(t *testing.T) {
+	events := []models.Event{orderEvent("bad", "UserInput", `{"1":-1}`)}
+	if _, err := topologicalSort(events); err == nil || errors.Is(err, ErrCausalCycle) {
+		t.Errorf("topologicalSort() error = %v, want an invalid clock error", err)
+	}
+}
+
+func TestEventGraphTopologicalOrder(t *testing.T) {
+	eg := NewEventGraph(1, "node-1")
+	genesis := eg.AddEvent("Genesis", "genesis", "start", map[int]int{}, nil)
+	input := eg.AddEvent("UserInput", "input", "task", map[int]int{2: 1}, []string{genesis})
+	output := eg.AddEvent("MinerOutput", "output", "answer", map[int]int{1: 1, 2: 1}, []string{input})
+	vote := eg.AddEvent("ValidatorVote", "vote", "accept", map[int]int{1: 1, 2: 1, 3: 1}, []string{output})
+
+	ordered, err := eg.TopologicalOrder()
+	if err != nil {
+		t.Fatalf("TopologicalOrder() error = %v", err)
+	}
+	var ids []string
+	for _, event := range ordered {
+		ids = append(ids, event.ID)
+	}
+	if got, want := strings.Join(ids, ","), strings.Join([]string{genesis, input, output, vote}, ","); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package dgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+)
+
+// MaxCausalQueryDepth limits how many parent edges GetAncestors and
+// GetDescendants follow from the starting event
+var MaxCausalQueryDepth = 64
+
+// causalNode is an event as returned by a recursive causal query
+type causalNode struct {
+	models.Event
+	Parents  []causalNode `json:"parent,omitempty"`
+	Children []causalNode `json:"~parent,omitempty"`
+}
+
+// GetAncestors returns every committed event the given event causally depends on,
+// following parent edges transitively up to MaxCausalQueryDepth
+func GetAncestors(ctx context.Context, eventID string) ([]models.Event, error) {
+	return queryCausalClosure(ctx, eventID, "parent")
+}
+
+// GetDescendants returns every committed event that causally depends on the given
+// event, following reverse parent edges transitively up to MaxCausalQueryDepth
+func GetDescendants(ctx context.Context, eventID string) ([]models.Event, error) {
+	return queryCausalClosure(ctx, eventID, "~parent")
+}
+
+// queryCausalClosure walks the given edge from every event with the ID and
+// returns the distinct events reached, excluding the starting events
+func queryCausalClosure(ctx context.Context, eventID string, edge string) ([]models.Event, error) {
+	client := Client()
+	if client == nil {
+		return nil, fmt.Errorf("dgraph client not initialized")
+	}
+	if MaxCausalQueryDepth <= 0 {
+		return nil, fmt.Errorf("invalid causal query depth: %d", MaxCausalQueryDepth)
+	}
+
+	// Event IDs restart with each run, so several committed events may share an ID
+	query := fmt.Sprintf(`query causal($id: string) {
+		events(func: eq(id, $id)) @recurse(depth: %d, loop: false) {
+			uid
+			id
+			name
+			clock
+			depth
+			value
+			key
+			node
+			%s
+		}
+	}`, MaxCausalQueryDepth+1, edge)
+
+	txn := client.NewReadOnlyTxn()
+	defer txn.Discard(ctx)
+
+	resp, err := txn.QueryWithVars(ctx, query, map[string]string{"$id": eventID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causal events: %v", err)
+	}
+
+	var result struct {
+		Events []causalNode `json:"events"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode causal events: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, root := range result.Events {
+		seen[root.UID] = true
+	}
+
+	events := make([]models.Event, 0)
+	var collect func(nodes []causalNode)
+	collect = func(nodes []causalNode) {
+		for _, node := range nodes {
+			if !seen[node.UID] {
+				seen[node.UID] = true
+				events = append(events, node.Event)
+			}
+			collect(node.Parents)
+			collect(node.Children)
+		}
+	}
+	for _, root := range result.Events {
+		collect(root.Parents)
+		collect(root.Children)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,93 @@
+package dgraph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+)
+
+// commitDiamond commits the graph e1_1 <- e1_2 <- {e1_3, e1_4} <- e1_5
+func commitDiamond(t *testing.T) {
+	t.Helper()
+	eg := NewEventGraph(1, "node-1")
+	eg.AddEvent("GenesisState", "genesis_0", "genesis", map[int]int{}, nil)
+	eg.AddEvent("UserInput", "user_input_1", "input", map[int]int{2: 1}, []string{"e1_1"})
+	eg.AddEvent("MinerOutput", "miner_output_1", "output", map[int]int{1: 1, 2: 1}, []string{"e1_2"})
+	eg.AddEvent("InfoResponse", "info_response_1", "answer", map[int]int{2: 2}, []string{"e1_2"})
+	eg.AddEvent("RoundSuccess", "round_1_complete", "done", map[int]int{1: 1, 2: 3}, []string{"e1_3", "e1_4"})
+	if err := eg.CommitToGraph(); err != nil {
+		t.Fatalf("CommitToGraph() error: %v", err)
+	}
+}
+
+// eventIDs returns the sorted, comma-joined IDs of the events
+func eventIDs(events []models.Event) string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestCausalQueries(t *testing.T) {
+	connectFakeDgraph(t)
+	commitDiamond(t)
+
+	tests := []struct {
+		name  string
+		query func(context.Context, string) ([]models.Event, error)
+		id    string
+		want  string
+	}{
+		{"ancestors of merge", GetAncestors, "e1_5", "e1_1,e1_2,e1_3,e1_4"},
+		{"ancestors of branch", GetAncestors, "e1_3", "e1_1,e1_2"},
+		{"ancestors of genesis", GetAncestors, "e1_1", ""},
+		{"descendants of fork", GetDescendants, "e1_2", "e1_3,e1_4,e1_5"},
+		{"descendants of branch", GetDescendants, "e1_4", "e1_5"},
+		{"descendants of tip", GetDescendants, "e1_5", ""},
+		{"unknown event", GetAncestors, "e9_9", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := tt.query(context.Background(), tt.id)
+			if err != nil {
+				t.Fatalf("query error: %v", err)
+			}
+			if got := eventIDs(events); got != tt.want {
+				t.Errorf("events = [%s], want [%s]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCausalQueryDepthLimit(t *testing.T) {
+	connectFakeDgraph(t)
+	commitDiamond(t)
+
+	defer func(depth int) { MaxCausalQueryDepth = depth }(MaxCausalQueryDepth)
+	MaxCausalQueryDepth = 1
+
+	events, err := GetAncestors(context.Background(), "e1_5")
+	if err != nil {
+		t.Fatalf("GetAncestors() error: %v", err)
+	}
+	if got := eventIDs(events); got != "e1_3,e1_4" {
+		t.Errorf("ancestors within depth 1 = [%s], want [e1_3,e1_4]", got)
+	}
+
+	MaxCausalQueryDepth = 0
+	if _, err := GetAncestors(context.Background(), "e1_5"); err == nil {
+		t.Error("GetAncestors() accepted a zero query depth")
+	}
+}
+
+func TestCausalQueryWithoutClient(t *testing.T) {
+	if _, err := GetDescendants(context.Background(), "e1_1"); err == nil {
+		t.Error("GetDescendants() succeeded without a Dgraph client")
+	}
+}
@@ -15,10 +15,16 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph"
@@ -109,6 +115,142 @@ func waitForDgraph() error {
 	return fmt.Errorf("dgraph not ready after %d attempts", maxRetries)
 }
 
+// dgraphTLSConfig builds the TLS settings for the Dgraph connection. DGRAPH_TLS=true
+// enables TLS with the system roots, and DGRAPH_TLS_CA_FILE trusts a custom CA instead.
+// Returns nil (an insecure connection) when neither is set.
+func dgraphTLSConfig() (*tls.Config, error) {
+	caFile := os.Getenv("DGRAPH_TLS_CA_FILE")
+	if os.Getenv("DGRAPH_TLS") != "true" && caFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read DGRAPH_TLS_CA_FILE: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in DGRAPH_TLS_CA_FILE %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// startStatsServer exposes the graph adapter's subnet stats, epoch metrics, finalized
+// epochs and causally ordered events over HTTP so external dashboards and visualization
+// tools can poll them while the demo is running
+func startStatsServer(addr string, adapter *subnet.SubnetGraphAdapter) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(adapter.GetSubnetStats())
+	})
+	mux.HandleFunc("/epoch-metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(adapter.GetEpochMetrics())
+	})
+	mux.HandleFunc("/events/causal-order", func(w http.ResponseWriter, r *http.Request) {
+		events, err := adapter.TopologicalOrder()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	})
+	mux.HandleFunc("/epochs/{number}", func(w http.ResponseWriter, r *http.Request) {
+		epochNumber, err := strconv.Atoi(r.PathValue("number"))
+		if err != nil {
+			http.Error(w, "invalid epoch number", http.StatusBadRequest)
+			return
+		}
+		epochData, err := adapter.GetEpochDetails(epochNumber)
+		if errors.Is(err, subnet.ErrEpochNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(epochData)
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Printf("⚠️  Stats server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("📈 Subnet stats server listening on %s (GET /stats, /epoch-metrics, /epochs/{number}, /events/causal-order)\n", addr)
+}
+
+// newDemoCoordinator creates the demo coordinator, using stake-based validator
+// weights from VALIDATOR_WEIGHTS_FILE when configured
+func newDemoCoordinator(subnetID string) *demo.DemoCoordinator {
+	weightsFile := os.Getenv("VALIDATOR_WEIGHTS_FILE")
+	if weightsFile == "" {
+		return demo.NewDemoCoordinator(subnetID)
+	}
+
+	weights, err := subnet.LoadValidatorWeights(weightsFile)
+	if err == nil {
+		var coordinator *demo.DemoCoordinator
+		if coordinator, err = demo.NewDemoCoordinatorWithWeights(subnetID, weights); err == nil {
+			fmt.Printf("⚖️  Loaded %d validator weights from %s\n", len(weights), weightsFile)
+			return coordinator
+		}
+	}
+
+	fmt.Printf("⚠️  Invalid validator weights: %v\n", err)
+	fmt.Println("Continuing with equal validator weights...")
+	return demo.NewDemoCoordinator(subnetID)
+}
+
+// configureBootstrapPolicy applies a VLC bootstrap policy to every validator.
+// VLC_BOOTSTRAP_MAX bounds first clock values under accept-zero-only, and
+// VLC_BOOTSTRAP_ALLOWLIST ("participantID:startValue,...") lists the senders allowed
+// under require-preauthorized; it defaults to the miner starting at 1.
+func configureBootstrapPolicy(coordinator *demo.DemoCoordinator, policy subnet.BootstrapPolicy) {
+	var maxValue uint64
+	if bound := os.Getenv("VLC_BOOTSTRAP_MAX"); bound != "" {
+		value, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			fmt.Printf("⚠️  Invalid VLC_BOOTSTRAP_MAX %q - first clock values bounded at 1\n", bound)
+		} else {
+			maxValue = value
+		}
+	}
+
+	allowlist := map[uint64]uint64{coordinator.Miner.ParticipantID: 1}
+	if entries := os.Getenv("VLC_BOOTSTRAP_ALLOWLIST"); entries != "" {
+		parsed := make(map[uint64]uint64)
+		for _, entry := range strings.Split(entries, ",") {
+			idPart, valuePart, found := strings.Cut(strings.TrimSpace(entry), ":")
+			senderID, idErr := strconv.ParseUint(idPart, 10, 64)
+			startValue, valueErr := strconv.ParseUint(valuePart, 10, 64)
+			if !found || idErr != nil || valueErr != nil {
+				fmt.Printf("⚠️  Invalid VLC_BOOTSTRAP_ALLOWLIST entry %q - skipping\n", entry)
+				continue
+			}
+			parsed[senderID] = startValue
+		}
+		allowlist = parsed
+	}
+
+	for _, validator := range coordinator.Validators {
+		validator.BootstrapPolicy = policy
+		validator.MaxBootstrapValue = maxValue
+		for senderID, startValue := range allowlist {
+			validator.AuthorizeBootstrap(senderID, startValue)
+		}
+	}
+	fmt.Printf("🔐 VLC bootstrap policy: %s\n", policy)
+}
+
 // main demonstrates the per-epoch PoCW integration
 func main() {
 	// Check if running in subnet-only mode
@@ -141,21 +283,186 @@ func main() {
 	if err := waitForDgraph(); err != nil {
 		fmt.Printf("Dgraph not available: %v\n", err)
 		fmt.Println("Running demo without graph visualization...")
+	} else if tlsConfig, err := dgraphTLSConfig(); err != nil {
+		fmt.Printf("Dgraph TLS misconfigured: %v\n", err)
+		fmt.Println("Running demo without graph visualization...")
 	} else {
 		fmt.Println("Initializing Dgraph connection...")
-		dgraph.InitDgraph("localhost:9080")
-		fmt.Println("Dgraph initialized successfully!")
+		if err := dgraph.InitDgraphTLS("localhost:9080", tlsConfig); err != nil {
+			fmt.Printf("Dgraph initialization failed: %v\n", err)
+			fmt.Println("Running demo without graph visualization...")
+		} else {
+			fmt.Println("Dgraph initialized successfully!")
+
+			// Watch the connection so outages and recoveries are reported as they happen
+			stopHealthCheck := dgraph.StartHealthCheck(30 * time.Second)
+			defer close(stopHealthCheck)
+		}
 	}
 
 	// Create demo coordinator with per-epoch callback integration  
-	coordinator := demo.NewDemoCoordinator("per-epoch-subnet-001")
+	coordinator := newDemoCoordinator("per-epoch-subnet-001")
+
+	// Optionally process tasks with an external model instead of canned demo responses
+	if modelURL := os.Getenv("TASK_PROCESSOR_URL"); modelURL != "" {
+		coordinator.Miner.SetTaskProcessor(subnet.NewHTTPTaskProcessor(subnet.HTTPTaskProcessorConfig{
+			Endpoint: modelURL,
+			APIKey:   os.Getenv("TASK_PROCESSOR_API_KEY"),
+		}))
+		fmt.Printf("🤖 Miner tasks processed by model endpoint %s\n", modelURL)
+	}
+
+	// Optionally replace the built-in scenarios with user-provided inputs
+	if inputsFile := os.Getenv("DEMO_INPUTS_FILE"); inputsFile != "" {
+		if err := coordinator.LoadInputsFromFile(inputsFile); err != nil {
+			fmt.Printf("⚠️  Failed to load demo inputs: %v\n", err)
+			fmt.Println("Continuing with built-in demo inputs...")
+		} else {
+			fmt.Printf("📄 Loaded demo inputs from %s\n", inputsFile)
+		}
+	}
+
+	// Optionally change how an exact 50/50 validator split is decided
+	if tieBreak := os.Getenv("CONSENSUS_TIE_BREAK"); tieBreak != "" {
+		if policy, err := subnet.ParseTieBreakPolicy(tieBreak); err != nil {
+			fmt.Printf("⚠️  %v - using %s\n", err, subnet.RejectOnTie)
+		} else {
+			coordinator.TieBreak = policy
+			fmt.Printf("⚖️  Consensus ties resolved with %s\n", policy)
+		}
+	}
+
+	// Optionally require a minimum validator participation for consensus
+	if minVotes := os.Getenv("QUORUM_MIN_VOTES"); minVotes != "" {
+		if count, err := strconv.Atoi(minVotes); err != nil || count < 0 {
+			fmt.Printf("⚠️  Invalid QUORUM_MIN_VOTES %q - no minimum vote count\n", minVotes)
+		} else {
+			coordinator.MinVotes = count
+		}
+	}
+	if quorumWeight := os.Getenv("QUORUM_WEIGHT"); quorumWeight != "" {
+		if weight, err := strconv.ParseFloat(quorumWeight, 64); err != nil || weight < 0 || weight > 1 {
+			fmt.Printf("⚠️  Invalid QUORUM_WEIGHT %q - no minimum voting weight\n", quorumWeight)
+		} else {
+			coordinator.QuorumWeight = weight
+		}
+	}
+
+	// Optionally change how many clarification requests a round may make
+	if maxInfo := os.Getenv("MAX_INFO_REQUESTS"); maxInfo != "" {
+		if limit, err := strconv.Atoi(maxInfo); err != nil || limit < 1 {
+			fmt.Printf("⚠️  Invalid MAX_INFO_REQUESTS %q - using %d\n", maxInfo, demo.DefaultMaxInfoRequests)
+		} else {
+			coordinator.MaxInfoRequests = limit
+		}
+	}
+
+	// Optionally reject peer clocks that advance a participant implausibly far
+	if maxJump := os.Getenv("MAX_CLOCK_JUMP"); maxJump != "" {
+		if jump, err := strconv.ParseUint(maxJump, 10, 64); err != nil {
+			fmt.Printf("⚠️  Invalid MAX_CLOCK_JUMP %q - clock jumps unbounded\n", maxJump)
+		} else {
+			coordinator.Miner.MaxClockJump = jump
+			for _, validator := range coordinator.Validators {
+				validator.MaxClockJump = jump
+			}
+		}
+	}
+
+	// Optionally restrict which first clocks validators trust when bootstrapping a sender
+	if bootstrap := os.Getenv("VLC_BOOTSTRAP_POLICY"); bootstrap != "" {
+		if policy, err := subnet.ParseBootstrapPolicy(bootstrap); err != nil {
+			fmt.Printf("⚠️  %v - using %s\n", err, subnet.AcceptAnyBootstrap)
+		} else {
+			configureBootstrapPolicy(coordinator, policy)
+		}
+	}
+
+	// Optionally persist adapter state so an interrupted epoch resumes after a restart
+	if stateFile := os.Getenv("ADAPTER_STATE_FILE"); stateFile != "" && coordinator.GraphAdapter != nil {
+		coordinator.GraphAdapter.SetStateStore(subnet.NewFileStateStore(stateFile))
+		if err := coordinator.ResumeFromStore(); err == nil {
+			fmt.Printf("💾 Resumed subnet state from %s\n", stateFile)
+		} else if !errors.Is(err, subnet.ErrNoSavedState) {
+			fmt.Printf("⚠️  Failed to resume subnet state: %v\n", err)
+			fmt.Println("Continuing with a fresh epoch...")
+		}
+	}
+
+	// Optionally keep finalized epoch details on disk so they survive restarts
+	if epochDir := os.Getenv("EPOCH_STORE_DIR"); epochDir != "" && coordinator.GraphAdapter != nil {
+		if store, err := subnet.NewFileEpochStore(epochDir); err != nil {
+			fmt.Printf("⚠️  Invalid EPOCH_STORE_DIR: %v\n", err)
+			fmt.Println("Keeping epoch details in memory...")
+		} else {
+			coordinator.GraphAdapter.SetEpochStore(store)
+		}
+	}
+
+	// Optionally reward rounds on validator consensus alone, even if the user rejects the output
+	if requireUser := os.Getenv("REWARD_REQUIRES_USER_ACCEPTANCE"); requireUser != "" && coordinator.GraphAdapter != nil {
+		if required, err := strconv.ParseBool(requireUser); err != nil {
+			fmt.Printf("⚠️  Invalid REWARD_REQUIRES_USER_ACCEPTANCE %q - user acceptance required\n", requireUser)
+		} else {
+			coordinator.GraphAdapter.SetRewardRequiresUserAcceptance(required)
+		}
+	}
+
+	// Optionally only submit epochs with enough successful rounds for KEY mining
+	if minRounds := os.Getenv("MIN_SUCCESSFUL_ROUNDS_FOR_MINING"); minRounds != "" && coordinator.GraphAdapter != nil {
+		if rounds, err := strconv.Atoi(minRounds); err != nil || rounds < 0 {
+			fmt.Printf("⚠️  Invalid MIN_SUCCESSFUL_ROUNDS_FOR_MINING %q - submitting every epoch\n", minRounds)
+		} else {
+			coordinator.GraphAdapter.SetMinSuccessfulRoundsForMining(rounds)
+		}
+	}
+
+	// Optionally expose live subnet stats for dashboards
+	if statsAddr := os.Getenv("SUBNET_STATS_ADDR"); statsAddr != "" && coordinator.GraphAdapter != nil {
+		startStatsServer(statsAddr, coordinator.GraphAdapter)
+	}
 	
+	// Optionally cap uncommitted graph events, auto-committing when the cap is hit
+	if maxPending := os.Getenv("MAX_PENDING_EVENTS"); maxPending != "" && coordinator.GraphAdapter != nil {
+		if limit, err := strconv.Atoi(maxPending); err != nil || limit < 0 {
+			fmt.Printf("⚠️  Invalid MAX_PENDING_EVENTS %q - pending events uncapped\n", maxPending)
+		} else {
+			coordinator.GraphAdapter.SetMaxPendingEvents(limit)
+		}
+	}
+
+	// Optionally split Dgraph commits into smaller mutations
+	if batchSize := os.Getenv("DGRAPH_COMMIT_BATCH_SIZE"); batchSize != "" && coordinator.GraphAdapter != nil {
+		if size, err := strconv.Atoi(batchSize); err != nil || size < 0 {
+			fmt.Printf("⚠️  Invalid DGRAPH_COMMIT_BATCH_SIZE %q - committing all events at once\n", batchSize)
+		} else {
+			coordinator.GraphAdapter.SetCommitBatchSize(size)
+		}
+	}
+
+	// Optionally finalize partial epochs after a maximum duration
+	if maxEpoch := os.Getenv("MAX_EPOCH_DURATION"); maxEpoch != "" && coordinator.GraphAdapter != nil {
+		if duration, err := time.ParseDuration(maxEpoch); err != nil || duration <= 0 {
+			fmt.Printf("⚠️  Invalid MAX_EPOCH_DURATION %q - time-based epochs disabled\n", maxEpoch)
+		} else {
+			stopEpochTimer := coordinator.GraphAdapter.StartEpochTimer(duration)
+			defer close(stopEpochTimer)
+			fmt.Printf("⏰ Partial epochs finalize after %v\n", duration)
+		}
+	}
+
 	// Set up HTTP bridge URL only if not in subnet-only mode
 	if !subnetOnlyMode && coordinator.GraphAdapter != nil {
 		fmt.Println("🔗 Setting up per-epoch HTTP bridge integration...")
 		
 		// Set the bridge URL for HTTP communication
 		coordinator.GraphAdapter.SetBridgeURL("http://localhost:3001")
+
+		// Sign epoch payloads so the bridge can reject forged submissions
+		if secret := os.Getenv("BRIDGE_SHARED_SECRET"); secret != "" {
+			coordinator.GraphAdapter.SetBridgeSecret(secret)
+			fmt.Println("🔏 Epoch payloads signed with HMAC-SHA256 (X-Epoch-Signature)")
+		}
 		
 		fmt.Println("✅ Per-epoch HTTP bridge configured successfully")
 		fmt.Println("📡 Graph adapter will send HTTP requests to JavaScript bridge")
@@ -19,6 +19,8 @@ const fs = require('fs').promises;
 const path = require('path');
 const http = require('http');
 const url = require('url');
+const crypto = require('crypto');
+const { parseAllowedOrigins, applyCors } = require('./bridge-cors');
 
 class PerEpochMainnetBridge {
     constructor() {
@@ -431,12 +433,11 @@ class PerEpochMainnetBridge {
     // Start HTTP server to receive epoch data from Go
     async startHttpServer() {
         const PORT = 3001;
+        const allowedOrigins = parseAllowedOrigins(process.env.ALLOWED_ORIGINS);
         
         this.httpServer = http.createServer((req, res) => {
-            // Handle CORS
-            res.setHeader('Access-Control-Allow-Origin', '*');
-            res.setHeader('Access-Control-Allow-Methods', 'GET, POST, OPTIONS');
-            res.setHeader('Access-Control-Allow-Headers', 'Content-Type');
+            // Handle CORS, restricted to ALLOWED_ORIGINS when set (wildcard for local development)
+            applyCors(allowedOrigins, req, res);
             
             if (req.method === 'OPTIONS') {
                 res.writeHead(200);
@@ -470,15 +471,42 @@ class PerEpochMainnetBridge {
         });
     }
 
+    // Verify the HMAC-SHA256 signature the Go adapter sends in X-Epoch-Signature.
+    // The HMAC is computed over the raw body bytes, so it must be checked before parsing.
+    // Verification is skipped when BRIDGE_SHARED_SECRET is not configured.
+    verifyEpochSignature(body, signature) {
+        const secret = process.env.BRIDGE_SHARED_SECRET;
+        if (!secret) {
+            return true;
+        }
+        if (typeof signature !== 'string') {
+            return false;
+        }
+        
+        const expected = crypto.createHmac('sha256', secret).update(body).digest('hex');
+        const received = Buffer.from(signature, 'utf8');
+        const computed = Buffer.from(expected, 'utf8');
+        return received.length === computed.length && crypto.timingSafeEqual(received, computed);
+    }
+
     // Handle epoch submission from Go
     async handleEpochSubmission(req, res) {
-        let body = '';
+        const chunks = [];
         
         req.on('data', chunk => {
-            body += chunk.toString();
+            chunks.push(chunk);
         });
         
         req.on('end', async () => {
+            const rawBody = Buffer.concat(chunks);
+            const body = rawBody.toString('utf8');
+            if (!this.verifyEpochSignature(rawBody, req.headers['x-epoch-signature'])) {
+                console.error('❌ Rejected epoch submission: invalid or missing X-Epoch-Signature');
+                res.writeHead(401, { 'Content-Type': 'application/json' });
+                res.end(JSON.stringify({ success: false, error: 'invalid epoch signature' }));
+                return;
+            }
+
             try {
                 const epochData = JSON.parse(body);
                 console.log(`\n🚀 RECEIVED EPOCH SUBMISSION FROM GO`);
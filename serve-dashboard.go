@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/cors"
 )
 
 func main() {
@@ -27,9 +29,10 @@ func main() {
 		fs.ServeHTTP(w, r)
 	})
 
-	// Enable CORS for local development
+	// Enable CORS, restricted to ALLOWED_ORIGINS when set (wildcard for local development)
+	allowedOrigins := cors.ParseAllowedOrigins(os.Getenv("ALLOWED_ORIGINS"))
 	http.HandleFunc("/contract_addresses.json", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		cors.SetAllowOrigin(w, r, allowedOrigins)
 		w.Header().Set("Content-Type", "application/json")
 		http.ServeFile(w, r, filepath.Join(dir, "contract_addresses.json"))
 	})
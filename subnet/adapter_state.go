@@ -0,0 +1,210 @@
+// Package subnet - Graph Adapter State Persistence
+//
+// This file lets a SubnetGraphAdapter survive a restart mid-epoch. The adapter's
+// epoch progress and round-chaining state are serialized to JSON and written to
+// a pluggable StateStore after every completed round, so a new process can
+// restore them and continue the same epoch instead of starting a fresh chain.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph"
+)
+
+// ErrNoSavedState is returned by StateStore.Load when nothing has been saved yet
+var ErrNoSavedState = errors.New("no saved adapter state")
+
+// StateStore persists serialized adapter state
+type StateStore interface {
+	Save(data []byte) error
+	Load() ([]byte, error) // Returns ErrNoSavedState if nothing has been saved
+}
+
+// FileStateStore stores adapter state in a single JSON file
+type FileStateStore struct {
+	Path string
+}
+
+// NewFileStateStore creates a state store backed by the file at path
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{Path: path}
+}
+
+// Save writes the state atomically by replacing the file with a fully written temporary file
+func (fs *FileStateStore) Save(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(fs.Path), filepath.Base(fs.Path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), fs.Path); err != nil {
+		return fmt.Errorf("failed to replace state file: %v", err)
+	}
+	return nil
+}
+
+// Load reads the saved state, returning ErrNoSavedState if the file does not exist
+func (fs *FileStateStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(fs.Path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoSavedState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	return data, nil
+}
+
+// AdapterState is the serialized form of a SubnetGraphAdapter's epoch and chaining state
+type AdapterState struct {
+	SubnetID         string                 `json:"subnetId"`
+	EpochCount       int                    `json:"epochCount"`
+	RoundsInEpoch    int                    `json:"roundsInEpoch"`
+	CompletedRounds  []string               `json:"completedRounds"`
+	EpochRequests    []string               `json:"epochRequests"`
+	CurrentRounds    map[string]*RoundData  `json:"currentRounds"`
+	RoundCounters    map[string]int         `json:"roundCounters"`
+	RequestCounter   int                    `json:"requestCounter"`
+	LastEventInChain string                 `json:"lastEventInChain"`
+	GenesisEventID   string                 `json:"genesisEventId"`
+	LastVLCState     map[int]int            `json:"lastVlcState"`
+	Graph            dgraph.EventGraphState `json:"graph"`
+}
+
+// SetStateStore sets the store the adapter saves its state to after every completed round
+func (sga *SubnetGraphAdapter) SetStateStore(store StateStore) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.stateStore = store
+}
+
+// SaveState serializes the adapter's epoch progress, round data and event graph state to JSON
+func (sga *SubnetGraphAdapter) SaveState() ([]byte, error) {
+	sga.mu.RLock()
+	defer sga.mu.RUnlock()
+	return sga.saveStateLocked()
+}
+
+// saveStateLocked serializes the adapter state; the caller must hold sga.mu
+func (sga *SubnetGraphAdapter) saveStateLocked() ([]byte, error) {
+	state := AdapterState{
+		SubnetID:         sga.SubnetID,
+		EpochCount:       sga.epochCount,
+		RoundsInEpoch:    sga.roundsInEpoch,
+		CompletedRounds:  sga.completedRounds,
+		EpochRequests:    sga.epochRequests,
+		CurrentRounds:    sga.currentRounds,
+		RoundCounters:    sga.roundCounters,
+		RequestCounter:   sga.requestCounter,
+		LastEventInChain: sga.lastEventInChain,
+		GenesisEventID:   sga.genesisEventID,
+		LastVLCState:     sga.lastVLCState,
+		Graph:            sga.EventGraph.ExportState(),
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal adapter state: %v", err)
+	}
+	return data, nil
+}
+
+// RestoreState replaces the adapter's state with one produced by SaveState, so the
+// next tracked round continues the saved epoch and chains from its last event.
+// The saved state must belong to the same subnet.
+func (sga *SubnetGraphAdapter) RestoreState(data []byte) error {
+	var state AdapterState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to unmarshal adapter state: %v", err)
+	}
+	if state.SubnetID != sga.SubnetID {
+		return fmt.Errorf("saved state belongs to subnet %q, not %q", state.SubnetID, sga.SubnetID)
+	}
+
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	sga.epochCount = state.EpochCount
+	sga.roundsInEpoch = state.RoundsInEpoch
+	sga.completedRounds = append(make([]string, 0, len(state.CompletedRounds)), state.CompletedRounds...)
+	sga.epochRequests = state.EpochRequests
+	sga.currentRounds = state.CurrentRounds
+	if sga.currentRounds == nil {
+		sga.currentRounds = make(map[string]*RoundData)
+	}
+	sga.roundCounters = state.RoundCounters
+	if sga.roundCounters == nil {
+		sga.roundCounters = make(map[string]int)
+	}
+	sga.requestCounter = state.RequestCounter
+	if sga.requestCounter < len(sga.roundCounters) {
+		sga.requestCounter = len(sga.roundCounters) // States saved before the counter was persisted
+	}
+	sga.lastEventInChain = state.LastEventInChain
+	sga.genesisEventID = state.GenesisEventID
+	sga.lastVLCState = state.LastVLCState
+	if sga.lastVLCState == nil {
+		sga.lastVLCState = make(map[int]int)
+	}
+	sga.lastEpochAt = sga.clock.Now() // Time-based epochs restart their window after a restore
+	sga.EventGraph.RestoreState(state.Graph)
+	return nil
+}
+
+// ReserveRequestNumbers reserves count request numbers and returns how many were
+// reserved before, so callers number their requests from the result plus one.
+// The counter is part of the saved state, so numbering continues after a restore
+// instead of reusing request IDs of the saved rounds.
+func (sga *SubnetGraphAdapter) ReserveRequestNumbers(count int) int {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	reserved := sga.requestCounter
+	sga.requestCounter += count
+	return reserved
+}
+
+// LoadStateFromStore restores the adapter from its state store.
+// Returns ErrNoSavedState if the store holds no state yet.
+func (sga *SubnetGraphAdapter) LoadStateFromStore() error {
+	sga.mu.RLock()
+	store := sga.stateStore
+	sga.mu.RUnlock()
+
+	if store == nil {
+		return fmt.Errorf("no state store configured")
+	}
+	data, err := store.Load()
+	if err != nil {
+		return err
+	}
+	return sga.RestoreState(data)
+}
+
+// persistStateLocked saves the adapter state to the configured store, if any.
+// Failures are logged rather than returned so tracking is never interrupted.
+// The caller must hold sga.mu.
+func (sga *SubnetGraphAdapter) persistStateLocked() {
+	if sga.stateStore == nil {
+		return
+	}
+	data, err := sga.saveStateLocked()
+	if err == nil {
+		err = sga.stateStore.Save(data)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Failed to persist adapter state: %v\n", err)
+	}
+}
@@ -0,0 +1,116 @@
+package subnet
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+func TestRestoredAdapterContinuesEpoch(t *testing.T) {
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "adapter-state.json"))
+
+	original := NewSubnetGraphAdapter("subnet-restart", 1, "node-1")
+	original.SetStateStore(store)
+	clock := vlc.New()
+	trackRound(t, original, clock, "req-1", 1, true)
+	lastEventID := trackRound(t, original, clock, "req-2", 2, true)
+	saved := original.GetSubnetStats()
+
+	restored := NewSubnetGraphAdapter("subnet-restart", 1, "node-1")
+	restored.SetStateStore(store)
+	if err := restored.LoadStateFromStore(); err != nil {
+		t.Fatalf("LoadStateFromStore() error = %v", err)
+	}
+
+	stats := restored.GetSubnetStats()
+	if stats.CurrentEpoch != saved.CurrentEpoch || stats.RoundsInEpoch != 2 || stats.TotalEvents != saved.TotalEvents {
+		t.Fatalf("restored stats = epoch %d, %d rounds, %d events; want epoch %d, 2 rounds, %d events",
+			stats.CurrentEpoch, stats.RoundsInEpoch, stats.TotalEvents, saved.CurrentEpoch, saved.TotalEvents)
+	}
+
+	// The next input chains from the last event saved before the restart
+	clock.Inc(2)
+	inputID := restored.TrackUserInput("req-3", "input for req-3", clock, "")
+	if parents := parentIDs(restored.EventGraph)[inputID]; len(parents) != 1 || parents[0] != lastEventID {
+		t.Errorf("UserInput after restore has parents %v, want [%s]", parents, lastEventID)
+	}
+
+	// Completing the third round finalizes the epoch that was interrupted
+	epochs := make(chan *EpochData, 1)
+	restored.SetEpochFinalizedCallback(func(epochNumber int, subnetID string, epochData *EpochData) {
+		epochs <- epochData
+	})
+	clock.Inc(1)
+	minerID := restored.TrackMinerResponse("req-3", &MinerResponseMessage{
+		OutputType:  OutputReady,
+		Output:      "output for req-3",
+		VLCClock:    clock.Copy(),
+		InputNumber: 3,
+	}, inputID)
+	clock.Inc(2)
+	restored.TrackRoundComplete("req-3", 3, clock, "ACCEPTED", true, "feedback", true, "OUTPUT DELIVERED TO USER", minerID)
+
+	select {
+	case epoch := <-epochs:
+		if epoch.EpochNumber != 1 {
+			t.Errorf("finalized epoch %d, want 1", epoch.EpochNumber)
+		}
+		if len(epoch.DetailedRounds) != 3 {
+			t.Errorf("finalized epoch has %d rounds, want 3", len(epoch.DetailedRounds))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("epoch was not finalized after the third round")
+	}
+}
+
+func TestLoadStateFromEmptyStore(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-empty", 1, "node-1")
+	sga.SetStateStore(NewFileStateStore(filepath.Join(t.TempDir(), "missing.json")))
+	if err := sga.LoadStateFromStore(); !errors.Is(err, ErrNoSavedState) {
+		t.Errorf("LoadStateFromStore() error = %v, want ErrNoSavedState", err)
+	}
+}
+
+func TestRestoredAdapterKeepsStateAndRequestNumbers(t *testing.T) {
+	source := NewSubnetGraphAdapter("subnet-numbers", 1, "node-1")
+	if got := source.ReserveRequestNumbers(4); got != 0 {
+		t.Errorf("first ReserveRequestNumbers() = %d, want 0", got)
+	}
+	clock := vlc.New()
+	trackRound(t, source, clock, "req-1", 1, true)
+	data, err := source.SaveState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewSubnetGraphAdapter("subnet-numbers", 1, "node-1")
+	if err := restored.RestoreState(data); err != nil {
+		t.Fatalf("RestoreState() error = %v", err)
+	}
+	if got := restored.ReserveRequestNumbers(2); got != 4 {
+		t.Errorf("ReserveRequestNumbers() after restore = %d, want 4", got)
+	}
+	if got := restored.LatestClock(); got.Compare(clock) != vlc.Equal {
+		t.Errorf("LatestClock() after restore = %s, want %s", got, clock)
+	}
+}
+
+func TestRestoreStateRejectsOtherSubnet(t *testing.T) {
+	source := NewSubnetGraphAdapter("subnet-a", 1, "node-1")
+	trackRound(t, source, vlc.New(), "req-1", 1, true)
+	data, err := source.SaveState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := NewSubnetGraphAdapter("subnet-b", 1, "node-1")
+	if err := target.RestoreState(data); err == nil {
+		t.Fatal("RestoreState() accepted state from another subnet")
+	}
+	if got := target.GetSubnetStats().RoundsInEpoch; got != 0 {
+		t.Errorf("RoundsInEpoch = %d after a rejected restore, want 0", got)
+	}
+}
@@ -0,0 +1,53 @@
+package subnet
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a wall-clock time source. It is unrelated to the VLC clocks that order
+// subnet events; it only supplies real time for epoch timing and timestamps, so
+// time-dependent behaviour can be driven deterministically with a FakeClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock reads the system time
+type RealClock struct{}
+
+// Now returns the current system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a manually controlled Clock for tests and simulations
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a fake clock stopped at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the fake clock's current time
+func (fc *FakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+// Advance moves the fake clock forward by d
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+}
+
+// Set moves the fake clock to the given time
+func (fc *FakeClock) Set(now time.Time) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = now
+}
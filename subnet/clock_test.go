@@ -0,0 +1,56 @@
+package subnet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	clock.Advance(90 * time.Second)
+	if got, want := clock.Now(), start.Add(90*time.Second); !got.Equal(want) {
+		t.Errorf("Now() after Advance = %v, want %v", got, want)
+	}
+	later := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock.Set(later)
+	if got := clock.Now(); !got.Equal(later) {
+		t.Errorf("Now() after Set = %v, want %v", got, later)
+	}
+}
+
+func TestMessageTimestampsFollowFakeClock(t *testing.T) {
+	forgetParticipantNames(t, "subnet-timestamps")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	miner := NewCoreMiner("miner-1", "subnet-timestamps", 1)
+	miner.SetClock(clock)
+	validator := NewCoreValidator("validator-1", "subnet-timestamps", UserInterfaceValidator, 1.0, 2)
+	validator.SetClock(clock)
+
+	response := miner.ProcessInput("task", 1, "req-1")
+	if got := response.Timestamp; got != start.Unix() {
+		t.Errorf("ProcessInput timestamp = %d, want %d", got, start.Unix())
+	}
+
+	clock.Advance(time.Minute)
+	if got, want := validator.RequestMoreInfo("req-1", "which region?").Timestamp, start.Add(time.Minute).Unix(); got != want {
+		t.Errorf("RequestMoreInfo timestamp = %d, want %d", got, want)
+	}
+
+	clock.Advance(time.Minute)
+	final := miner.ProcessAdditionalInfo("task", "EU", 1, "req-1")
+	if got, want := final.Timestamp, start.Add(2*time.Minute).Unix(); got != want {
+		t.Errorf("ProcessAdditionalInfo timestamp = %d, want %d", got, want)
+	}
+
+	clock.Advance(time.Minute)
+	if got, want := validator.VoteOnOutput(final).Timestamp, start.Add(3*time.Minute).Unix(); got != want {
+		t.Errorf("VoteOnOutput timestamp = %d, want %d", got, want)
+	}
+}
@@ -7,7 +7,6 @@ package subnet
 
 import (
 	"sync"
-	"time"
 
 	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
 )
@@ -29,6 +28,18 @@ type TaskProcessor interface {
 	ProcessAdditionalInfo(originalInput string, additionalInfo string, inputNumber int) string
 }
 
+// FollowUpTaskProcessor is an optional extension of TaskProcessor for processors that
+// may need several rounds of clarification. When implemented, CoreMiner uses it instead
+// of ProcessAdditionalInfo, so a follow-up may itself return NeedMoreInfo. Coordinators
+// must bound the number of info requests per task.
+type FollowUpTaskProcessor interface {
+	TaskProcessor
+
+	// ProcessFollowUp handles processing after the user provided additional context.
+	// Returns the same values as ProcessTask.
+	ProcessFollowUp(originalInput string, additionalInfo string, inputNumber int) (outputType MinerOutputType, output string, infoRequest string)
+}
+
 // CoreMiner represents a generic AI agent (miner) in the PoCW subnet architecture.
 // It processes user tasks while maintaining causal consistency through Vector Logical Clocks.
 // The miner's behavior is customizable through pluggable TaskProcessor implementations.
@@ -39,12 +50,15 @@ type TaskProcessor interface {
 //   - Clock values enable validators to verify causal ordering of operations
 type CoreMiner struct {
 	// Identity and network information
-	ID       string // Unique miner identifier
-	SubnetID string // Subnet this miner belongs to
+	ID            string // Unique miner identifier
+	SubnetID      string // Subnet this miner belongs to
+	ParticipantID uint64 // VLC participant ID incremented for this miner's operations
+	MaxClockJump  uint64 // Largest per-participant advance accepted from a validator clock (0 = unlimited)
 	
 	// VLC-based causal consistency
 	VLCClock *vlc.Clock   // Vector clock tracking logical time of operations
 	mu       sync.RWMutex // Protects concurrent access to miner state
+	clock    Clock        // Wall-clock time source for message timestamps
 
 	// Processing history and state
 	processedInputs map[int]*MinerResponseMessage // Audit trail of processed tasks
@@ -59,17 +73,29 @@ type CoreMiner struct {
 // Parameters:
 //   - id: Unique identifier for this miner (e.g., "miner-1")
 //   - subnetID: Identifier of the subnet this miner joins
+//   - participantID: VLC participant ID for this miner (the demo uses 1)
 //
 // Returns a miner with initialized VLC clock (starting at 0) and empty processing history.
-func NewCoreMiner(id, subnetID string) *CoreMiner {
+func NewCoreMiner(id, subnetID string, participantID uint64) *CoreMiner {
+	RegisterParticipantName(subnetID, participantID, id)
 	return &CoreMiner{
 		ID:              id,
 		SubnetID:        subnetID,
+		ParticipantID:   participantID,
 		VLCClock:        vlc.New(), // Initialize VLC clock
+		clock:           RealClock{},
 		processedInputs: make(map[int]*MinerResponseMessage),
 	}
 }
 
+// SetClock replaces the wall-clock time source used for message timestamps,
+// e.g. with a FakeClock for deterministic tests
+func (m *CoreMiner) SetClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+}
+
 // SetTaskProcessor sets the task processing strategy
 func (m *CoreMiner) SetTaskProcessor(processor TaskProcessor) {
 	m.taskProcessor = processor
@@ -79,12 +105,12 @@ func (m *CoreMiner) SetTaskProcessor(processor TaskProcessor) {
 // This method represents the first logical operation in the PoCW protocol.
 //
 // Simplified VLC Behavior: 
-//   - Miner ID = 1, Validator-1 ID = 2
-//   - Only these two participants maintain VLC clocks
+//   - Each participant increments its own ParticipantID (demo: Miner = 1, Validator-1 = 2)
+//   - Only the miner and the UI validator maintain VLC clocks in the demo
 //   - Other validators (2-4) just vote without VLC tracking
 //
 // Process:
-//   1. Increment VLC clock for miner (ID = 1)
+//   1. Increment VLC clock for miner's ParticipantID
 //   2. Use pluggable TaskProcessor to analyze input
 //   3. Generate response with either solution (OutputReady) or info request (NeedMoreInfo)
 //   4. Store response in processing history
@@ -94,8 +120,8 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Increment VLC clock for miner processing (miner ID = 1)
-	m.VLCClock.Inc(1)
+	// Increment VLC clock for miner processing
+	m.VLCClock.Inc(m.ParticipantID)
 
 	response := &MinerResponseMessage{
 		SubnetMessage: SubnetMessage{
@@ -103,9 +129,9 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 			RequestID: requestID,
 			Type:      MinerResponseType,
 			Sender:    m.ID,
-			Timestamp: time.Now().Unix(),
+			Timestamp: m.clock.Now().Unix(),
 		},
-		VLCClock:    m.VLCClock,
+		VLCClock:    m.VLCClock.Copy(), // Snapshot so later increments don't alter this message
 		InputNumber: inputNumber,
 	}
 
@@ -133,9 +159,10 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 // additional context, so miner processes this as the next logical operation in the round.
 //
 // Process:
-//   1. Increment VLC clock for miner (ID = 1) - represents work of processing additional context
+//   1. Increment VLC clock for miner's ParticipantID - represents work of processing additional context
 //   2. Use pluggable TaskProcessor to process original + additional context
-//   3. Generate final response with OutputReady type
+//   3. Generate final response with OutputReady type (a FollowUpTaskProcessor may
+//      instead return NeedMoreInfo to ask another question)
 //   4. Update processing history with final response
 //
 // Called after ProcessInput() returned NeedMoreInfo and user provided clarification.
@@ -143,8 +170,8 @@ func (m *CoreMiner) ProcessAdditionalInfo(originalInput string, additionalInfo s
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Increment VLC clock for processing additional context (miner ID = 1)
-	m.VLCClock.Inc(1)
+	// Increment VLC clock for processing additional context
+	m.VLCClock.Inc(m.ParticipantID)
 
 	response := &MinerResponseMessage{
 		SubnetMessage: SubnetMessage{
@@ -152,15 +179,20 @@ func (m *CoreMiner) ProcessAdditionalInfo(originalInput string, additionalInfo s
 			RequestID: requestID,
 			Type:      MinerResponseType,
 			Sender:    m.ID,
-			Timestamp: time.Now().Unix(),
+			Timestamp: m.clock.Now().Unix(),
 		},
 		OutputType:  OutputReady,
-		VLCClock:    m.VLCClock, // Use incremented clock
+		VLCClock:    m.VLCClock.Copy(), // Snapshot of the incremented clock
 		InputNumber: inputNumber,
 	}
 
 	// Use pluggable task processor for additional info
-	if m.taskProcessor != nil {
+	if followUp, ok := m.taskProcessor.(FollowUpTaskProcessor); ok {
+		outputType, output, infoRequest := followUp.ProcessFollowUp(originalInput, additionalInfo, inputNumber)
+		response.OutputType = outputType
+		response.Output = output
+		response.InfoRequest = infoRequest
+	} else if m.taskProcessor != nil {
 		response.Output = m.taskProcessor.ProcessAdditionalInfo(originalInput, additionalInfo, inputNumber)
 	} else {
 		// Default: simple concatenation
@@ -181,13 +213,27 @@ func (m *CoreMiner) GetCurrentClock() *vlc.Clock {
 
 // UpdateValidatorClock synchronizes miner's VLC with validator operations
 // Called when the miner receives information about validator-1's VLC updates
-// This maintains causal consistency between the two VLC participants
-func (m *CoreMiner) UpdateValidatorClock(validatorClock *vlc.Clock) {
+// This maintains causal consistency between the two VLC participants.
+// Returns an error without merging if any participant advances by more than MaxClockJump.
+func (m *CoreMiner) UpdateValidatorClock(validatorClock *vlc.Clock) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	if err := checkClockJump(m.SubnetID, m.VLCClock, validatorClock, m.MaxClockJump); err != nil {
+		return err
+	}
 	
 	// Merge validator's VLC state into miner's clock for causal consistency
 	m.VLCClock.Merge([]*vlc.Clock{validatorClock})
+	return nil
+}
+
+// SeedClock merges a trusted clock, such as a genesis or restored graph clock, into the
+// miner's VLC. Unlike UpdateValidatorClock it is not limited by MaxClockJump.
+func (m *CoreMiner) SeedClock(clock *vlc.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.VLCClock.Merge([]*vlc.Clock{clock})
 }
 
 // GetProcessedInputs returns all processed inputs for debugging
@@ -4,9 +4,12 @@
 package subnet
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"sync"
-	"time"
 
 	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
 )
@@ -40,6 +43,10 @@ type UserInteractionHandler interface {
 	// SimulateUserInteraction models user feedback on miner output.
 	// Returns user acceptance decision and textual feedback.
 	SimulateUserInteraction(inputNumber int, output string) (accept bool, feedback string)
+
+	// ProvideAdditionalInfo answers a miner's info request on behalf of the user.
+	// Returns an error if the additional context cannot be obtained.
+	ProvideAdditionalInfo(requestID, question string) (string, error)
 }
 
 // CoreValidator represents a generic validator node in the PoCW subnet architecture.
@@ -47,14 +54,22 @@ type UserInteractionHandler interface {
 // The validator tracks miner state using Vector Logical Clocks to ensure causal consistency.
 type CoreValidator struct {
 	// Identity and network information
-	ID       string        // Unique validator identifier
-	SubnetID string        // Subnet this validator belongs to
-	Role     ValidatorRole // Validator's specific role in the subnet
-	Weight   float64       // Voting weight in consensus (e.g., 0.25 for 1/4 validators)
+	ID            string        // Unique validator identifier
+	SubnetID      string        // Subnet this validator belongs to
+	Role          ValidatorRole // Validator's specific role in the subnet
+	Weight        float64       // Voting weight in consensus (e.g., 0.25 for 1/4 validators)
+	ParticipantID uint64        // VLC participant ID incremented for this validator's operations
+	MaxClockJump  uint64        // Largest per-participant advance accepted from a miner clock (0 = unlimited)
 	
 	// VLC-based state tracking
 	MinerClock *vlc.Clock // Vector clock tracking miner's causal state
 	mu         sync.RWMutex // Protects concurrent access to validator state
+	clock      Clock        // Wall-clock time source for message timestamps
+
+	// VLC bootstrap policy for the first clock seen from each sender
+	BootstrapPolicy    BootstrapPolicy   // How a first clock is checked before it is trusted (empty = AcceptAnyBootstrap)
+	MaxBootstrapValue  uint64            // Largest first clock value AcceptZeroOnlyBootstrap accepts (0 = 1)
+	bootstrapAllowlist map[uint64]uint64 // Expected first clock value of each preauthorized sender
 
 	// Consensus and quality assessment
 	assessments map[string]*QualityAssessment // Per-request quality tracking
@@ -73,17 +88,75 @@ type CoreValidator struct {
 //   - subnetID: Identifier of the subnet this validator joins
 //   - role: Validator's role (UserInterfaceValidator or ConsensusValidator)
 //   - weight: Voting weight in consensus decisions (typically 1.0/N for N validators)
-func NewCoreValidator(id, subnetID string, role ValidatorRole, weight float64) *CoreValidator {
+//   - participantID: VLC participant ID for this validator (the demo's Validator-1 uses 2)
+func NewCoreValidator(id, subnetID string, role ValidatorRole, weight float64, participantID uint64) *CoreValidator {
+	RegisterParticipantName(subnetID, participantID, id)
 	return &CoreValidator{
-		ID:          id,
-		SubnetID:    subnetID,
-		Role:        role,
-		Weight:      weight,
-		MinerClock:  vlc.New(), // Initialize VLC clock
-		assessments: make(map[string]*QualityAssessment),
+		ID:            id,
+		SubnetID:      subnetID,
+		Role:          role,
+		Weight:        weight,
+		ParticipantID: participantID,
+		MinerClock:    vlc.New(), // Initialize VLC clock
+		clock:         RealClock{},
+		assessments:   make(map[string]*QualityAssessment),
 	}
 }
 
+// ValidatorWeight assigns a consensus voting weight to a validator ID
+type ValidatorWeight struct {
+	ID     string  `json:"id"`
+	Weight float64 `json:"weight"`
+}
+
+// LoadValidatorWeights reads a JSON array of validator weights from a config file
+func LoadValidatorWeights(path string) ([]ValidatorWeight, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validator weights: %v", err)
+	}
+
+	var weights []ValidatorWeight
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("failed to parse validator weights: %v", err)
+	}
+	return weights, nil
+}
+
+// NormalizeValidatorWeights scales weights so they sum to 1.0, matching the >50%
+// consensus threshold used by QualityAssessment. Weights must be non-negative,
+// IDs unique, and at least one weight positive.
+func NormalizeValidatorWeights(weights []ValidatorWeight) ([]ValidatorWeight, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("at least one validator weight is required")
+	}
+
+	seen := make(map[string]bool, len(weights))
+	total := 0.0
+	for _, vw := range weights {
+		if vw.ID == "" {
+			return nil, fmt.Errorf("validator weight entry is missing an ID")
+		}
+		if seen[vw.ID] {
+			return nil, fmt.Errorf("duplicate validator ID %s in weights", vw.ID)
+		}
+		seen[vw.ID] = true
+		if vw.Weight < 0 {
+			return nil, fmt.Errorf("validator %s has negative weight %.4f", vw.ID, vw.Weight)
+		}
+		total += vw.Weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("validator weights sum to zero")
+	}
+
+	normalized := make([]ValidatorWeight, len(weights))
+	for i, vw := range weights {
+		normalized[i] = ValidatorWeight{ID: vw.ID, Weight: vw.Weight / total}
+	}
+	return normalized, nil
+}
+
 // SetQualityAssessor sets the quality assessment strategy
 func (v *CoreValidator) SetQualityAssessor(assessor QualityAssessor) {
 	v.qualityAssessor = assessor
@@ -94,12 +167,99 @@ func (v *CoreValidator) SetUserInteractionHandler(handler UserInteractionHandler
 	v.userInteractionHandler = handler
 }
 
+// SetClock replaces the wall-clock time source used for message timestamps,
+// e.g. with a FakeClock for deterministic tests
+func (v *CoreValidator) SetClock(clock Clock) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.clock = clock
+}
+
+// BootstrapPolicy decides whether ValidateSequence trusts the first clock it sees
+// from a sender. Bootstrapping merges that clock unconditionally, so without a
+// policy a sender can start from an arbitrary clock value. The restrictive policies
+// also reject first clocks carrying other participants' entries ahead of the
+// validator's own view, since those would be merged too.
+type BootstrapPolicy string
+
+const (
+	AcceptAnyBootstrap            BootstrapPolicy = "accept-any"            // Any first clock is accepted (default)
+	AcceptZeroOnlyBootstrap       BootstrapPolicy = "accept-zero-only"      // The sender's first value must be between 1 and MaxBootstrapValue
+	RequirePreauthorizedBootstrap BootstrapPolicy = "require-preauthorized" // The sender must be authorized, starting at its expected value
+)
+
+// ParseBootstrapPolicy converts a policy name into a BootstrapPolicy.
+// An empty name selects AcceptAnyBootstrap.
+func ParseBootstrapPolicy(name string) (BootstrapPolicy, error) {
+	switch policy := BootstrapPolicy(name); policy {
+	case "":
+		return AcceptAnyBootstrap, nil
+	case AcceptAnyBootstrap, AcceptZeroOnlyBootstrap, RequirePreauthorizedBootstrap:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown VLC bootstrap policy %q", name)
+	}
+}
+
+// AuthorizeBootstrap allows a sender to bootstrap under RequirePreauthorizedBootstrap.
+// Its first clock must carry exactly startValue for the sender's own participant ID.
+func (v *CoreValidator) AuthorizeBootstrap(senderID uint64, startValue uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.bootstrapAllowlist == nil {
+		v.bootstrapAllowlist = make(map[uint64]uint64)
+	}
+	v.bootstrapAllowlist[senderID] = startValue
+}
+
+// checkBootstrap applies the bootstrap policy to a sender's first clock; the caller must hold v.mu
+func (v *CoreValidator) checkBootstrap(incomingClock *vlc.Clock, senderID uint64) error {
+	value := incomingClock.Values[senderID]
+
+	switch v.BootstrapPolicy {
+	case "", AcceptAnyBootstrap:
+		return nil
+	case AcceptZeroOnlyBootstrap:
+		maxValue := v.MaxBootstrapValue
+		if maxValue == 0 {
+			maxValue = 1
+		}
+		if value < 1 || value > maxValue {
+			return fmt.Errorf("first clock value %d is outside 1..%d", value, maxValue)
+		}
+	case RequirePreauthorizedBootstrap:
+		expected, ok := v.bootstrapAllowlist[senderID]
+		if !ok {
+			return fmt.Errorf("sender is not preauthorized")
+		}
+		if value != expected {
+			return fmt.Errorf("first clock value %d, expected %d", value, expected)
+		}
+	default:
+		return fmt.Errorf("unknown VLC bootstrap policy %q", v.BootstrapPolicy)
+	}
+
+	// The whole clock is merged, so other participants' entries must not run ahead of
+	// what this validator has already seen
+	for participantID, otherValue := range incomingClock.Values {
+		if participantID == senderID {
+			continue
+		}
+		if local := v.MinerClock.Values[participantID]; otherValue > local {
+			return fmt.Errorf("%s value %d is ahead of the local value %d",
+				getParticipantName(v.SubnetID, participantID), otherValue, local)
+		}
+	}
+	return nil
+}
+
 // ValidateSequence validates the causal ordering using Vector Logical Clocks.
-// In the simplified round-based system, only Miner (ID=1) and Validator-1 (ID=2) 
-// participate in VLC tracking.
+// Each sender is identified by its VLC participant ID, assigned to every miner and
+// validator at construction.
 //
 // VLC Validation Rules:
-//   - Bootstrap: Accept first message from any participant
+//   - Bootstrap: Accept the first message from a participant if BootstrapPolicy allows it
 //   - Increment: Accept +1 increment for the sending participant
 //   - Cross-tracking: Validate causal consistency between both participants
 //
@@ -111,24 +271,76 @@ func (v *CoreValidator) ValidateSequence(incomingClock *vlc.Clock, senderID uint
 	// Check if this sender is bootstrapped in our tracking
 	_, exists := v.MinerClock.Values[senderID]
 	if !exists {
-		// First message from this sender - bootstrap and accept
+		// First message from this sender - bootstrap if the policy trusts its clock
+		if err := v.checkBootstrap(incomingClock, senderID); err != nil {
+			fmt.Printf("Validator %s: Rejected %s bootstrap clock %s under %s policy - %v\n",
+				v.ID, getParticipantName(v.SubnetID, senderID), incomingClock, v.bootstrapPolicyName(), err)
+			return false
+		}
 		v.MinerClock.Merge([]*vlc.Clock{incomingClock})
-		fmt.Printf("Validator %s: Bootstrapped %s clock - %v\n", v.ID, getParticipantName(senderID), incomingClock.Values)
+		fmt.Printf("Validator %s: Bootstrapped %s clock - %s\n", v.ID, getParticipantName(v.SubnetID, senderID), incomingClock)
 		return true
 	}
 
 	// Validate +1 increment for the sender
 	if v.MinerClock.IsPlusOneIncrement(incomingClock, senderID) {
 		v.MinerClock.Merge([]*vlc.Clock{incomingClock})
-		fmt.Printf("Validator %s: VLC sequence validated (+1) for %s - %v\n", v.ID, getParticipantName(senderID), incomingClock.Values)
+		fmt.Printf("Validator %s: VLC sequence validated (+1) for %s - %s\n", v.ID, getParticipantName(v.SubnetID, senderID), incomingClock)
 		return true
 	}
 
-	fmt.Printf("Validator %s: VLC sequence error for %s - expected +1 from %v, got %v\n",
-		v.ID, getParticipantName(senderID), v.MinerClock.Values, incomingClock.Values)
+	fmt.Printf("Validator %s: VLC sequence error for %s - expected +1 from %s, got %s (%s)\n",
+		v.ID, getParticipantName(v.SubnetID, senderID), v.MinerClock, incomingClock,
+		describeClockDiff(v.SubnetID, v.MinerClock.Diff(incomingClock)))
 	return false
 }
 
+// bootstrapPolicyName returns the effective bootstrap policy for log messages
+func (v *CoreValidator) bootstrapPolicyName() BootstrapPolicy {
+	if v.BootstrapPolicy == "" {
+		return AcceptAnyBootstrap
+	}
+	return v.BootstrapPolicy
+}
+
+// describeClockDiff renders per-participant clock deltas in participant ID order,
+// e.g. "Miner +2, Validator-1 -1"
+func describeClockDiff(subnetID string, diff map[uint64]int64) string {
+	if len(diff) == 0 {
+		return "no participant changed"
+	}
+	ids := make([]uint64, 0, len(diff))
+	for id := range diff {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s %+d", getParticipantName(subnetID, id), diff[id]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeClock formats each participant's clock value with its registered name,
+// e.g. "Miner=4, Validator-1=8"
+func describeClock(subnetID string, clock *vlc.Clock) string {
+	if clock == nil || len(clock.Values) == 0 {
+		return "no participants"
+	}
+	ids := make([]uint64, 0, len(clock.Values))
+	for id := range clock.Values {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s=%d", getParticipantName(subnetID, id), clock.Values[id]))
+	}
+	return strings.Join(parts, ", ")
+}
+
 // VoteOnOutput evaluates a miner's response and generates a consensus vote.
 // This method focuses purely on quality assessment - VLC validation should be done separately.
 //
@@ -157,7 +369,7 @@ func (v *CoreValidator) VoteOnOutput(response *MinerResponseMessage) *ValidatorV
 			RequestID: response.RequestID,
 			Type:      ValidatorVoteType,
 			Sender:    v.ID,
-			Timestamp: time.Now().Unix(),
+			Timestamp: v.clock.Now().Unix(),
 		},
 		ValidatorID:    v.ID,
 		Weight:         v.Weight,
@@ -179,7 +391,7 @@ func (v *CoreValidator) VoteOnOutput(response *MinerResponseMessage) *ValidatorV
 
 	// Add vote to assessment
 	assessment := v.assessments[response.RequestID]
-	assessment.AddVote(v.Weight, accept)
+	assessment.AddQualityVote(v.Weight, accept, quality)
 
 	fmt.Printf("Validator %s: Voted on Request %s - Accept: %t, Quality: %.2f\n",
 		v.ID, response.RequestID, accept, quality)
@@ -198,13 +410,17 @@ func (v *CoreValidator) RequestMoreInfo(requestID, question string) *InfoRequest
 		return nil // Only UI validator can request more info
 	}
 
+	v.mu.RLock()
+	clock := v.clock
+	v.mu.RUnlock()
+
 	return &InfoRequestMessage{
 		SubnetMessage: SubnetMessage{
 			SubnetID:  v.SubnetID,
 			RequestID: requestID,
 			Type:      InfoRequestType,
 			Sender:    v.ID,
-			Timestamp: time.Now().Unix(),
+			Timestamp: clock.Now().Unix(),
 		},
 		Question: question,
 	}
@@ -218,12 +434,10 @@ func (v *CoreValidator) GetAssessment(requestID string) *QualityAssessment {
 	if assessment, exists := v.assessments[requestID]; exists {
 		// Return a copy to avoid race conditions
 		return &QualityAssessment{
-			RequestID:   assessment.RequestID,
-			TotalWeight: assessment.TotalWeight,
-			AcceptVotes: assessment.AcceptVotes,
-			RejectVotes: assessment.RejectVotes,
-			VoteCount:   assessment.VoteCount,
-			Consensus:   assessment.Consensus,
+			Tally:     assessment.Tally,
+			RequestID: assessment.RequestID,
+			Consensus: assessment.Consensus,
+			TieBreak:  assessment.TieBreak,
 		}
 	}
 	return nil
@@ -237,13 +451,50 @@ func (v *CoreValidator) GetLastMinerClock() *vlc.Clock {
 }
 
 // UpdateMinerClock synchronizes validator's VLC with miner operations
-// Called when the validator receives miner responses to maintain causal consistency
-func (v *CoreValidator) UpdateMinerClock(minerClock *vlc.Clock) {
+// Called when the validator receives miner responses to maintain causal consistency.
+// Returns an error without merging if any participant advances by more than MaxClockJump.
+func (v *CoreValidator) UpdateMinerClock(minerClock *vlc.Clock) error {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+
+	if err := checkClockJump(v.SubnetID, v.MinerClock, minerClock, v.MaxClockJump); err != nil {
+		return err
+	}
 	
 	// Merge miner's VLC state into validator's clock for causal consistency
 	v.MinerClock.Merge([]*vlc.Clock{minerClock})
+	return nil
+}
+
+// SeedClock merges a trusted clock, such as a genesis or restored graph clock, into the
+// validator's VLC. Unlike UpdateMinerClock it is not limited by MaxClockJump.
+func (v *CoreValidator) SeedClock(clock *vlc.Clock) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.MinerClock.Merge([]*vlc.Clock{clock})
+}
+
+// checkClockJump rejects an incoming clock that advances any participant by more than
+// maxJump over the local clock. Merging is monotonic, so an inflated clock from a faulty
+// or malicious peer would otherwise poison local state permanently. maxJump 0 disables the check.
+func checkClockJump(subnetID string, local, incoming *vlc.Clock, maxJump uint64) error {
+	if maxJump == 0 {
+		return nil
+	}
+
+	diff := local.Diff(incoming)
+	ids := make([]uint64, 0, len(diff))
+	for id := range diff {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if delta := diff[id]; delta > 0 && uint64(delta) > maxJump {
+			return fmt.Errorf("clock for %s jumps by %d, exceeding max jump %d", getParticipantName(subnetID, id), delta, maxJump)
+		}
+	}
+	return nil
 }
 
 // IncrementValidatorClock increments validator's own VLC for validator operations
@@ -252,9 +503,8 @@ func (v *CoreValidator) IncrementValidatorClock() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	
-	const validatorID uint64 = 2 // Validator-1 ID in round-based system
-	v.MinerClock.Inc(validatorID)
-	fmt.Printf("Validator %s: Incremented VLC for validator operation - %v\n", v.ID, v.MinerClock.Values)
+	v.MinerClock.Inc(v.ParticipantID)
+	fmt.Printf("Validator %s: Incremented VLC for validator operation - %s\n", v.ID, v.MinerClock)
 }
 
 // SimulateUserInteraction uses pluggable user interaction logic
@@ -266,14 +516,55 @@ func (v *CoreValidator) SimulateUserInteraction(inputNumber int, output string)
 	return true, "This looks good, thank you!"
 }
 
-// getParticipantName returns human-readable name for VLC participant IDs
-func getParticipantName(id uint64) string {
-	switch id {
-	case 1:
-		return "Miner"
-	case 2:
-		return "Validator-1"
-	default:
-		return fmt.Sprintf("Participant-%d", id)
+// ProvideAdditionalInfo obtains the user's answer to a miner info request via the
+// pluggable user interaction handler
+func (v *CoreValidator) ProvideAdditionalInfo(requestID, question string) (string, error) {
+	if v.userInteractionHandler == nil {
+		return "", fmt.Errorf("validator %s has no user interaction handler for info requests", v.ID)
+	}
+	return v.userInteractionHandler.ProvideAdditionalInfo(requestID, question)
+}
+
+// participantNames maps each subnet's VLC participant IDs to human-readable names for
+// logging. Names are scoped by subnet, so subnets reusing participant IDs keep their own.
+var (
+	participantNames   = make(map[string]map[uint64]string)
+	participantNamesMu sync.RWMutex
+)
+
+// defaultParticipantNames keeps the demo's historical names for the Miner (1) and
+// Validator-1 (2) in every subnet
+var defaultParticipantNames = map[uint64]string{1: "Miner", 2: "Validator-1"}
+
+// RegisterParticipantName records a display name for a VLC participant ID in a subnet.
+// Names that are already registered in the subnet, and the default names, are kept,
+// so the first registration wins.
+func RegisterParticipantName(subnetID string, id uint64, name string) {
+	if _, isDefault := defaultParticipantNames[id]; isDefault {
+		return
+	}
+
+	participantNamesMu.Lock()
+	defer participantNamesMu.Unlock()
+	names, ok := participantNames[subnetID]
+	if !ok {
+		names = make(map[uint64]string)
+		participantNames[subnetID] = names
+	}
+	if _, exists := names[id]; !exists {
+		names[id] = name
+	}
+}
+
+// getParticipantName returns the human-readable name of a VLC participant in a subnet
+func getParticipantName(subnetID string, id uint64) string {
+	participantNamesMu.RLock()
+	defer participantNamesMu.RUnlock()
+	if name, exists := participantNames[subnetID][id]; exists {
+		return name
+	}
+	if name, exists := defaultParticipantNames[id]; exists {
+		return name
 	}
+	return fmt.Sprintf("Participant-%d", id)
 }
\ No newline at end of file
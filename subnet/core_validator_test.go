@@ -0,0 +1,247 @@
+package subnet
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+// clockOfValues builds a clock from participant/value pairs
+func clockOfValues(values map[uint64]uint64) *vlc.Clock {
+	c := vlc.New()
+	for id, value := range values {
+		c.Values[id] = value
+	}
+	return c
+}
+
+// forgetParticipantNames removes the subnet's registered names when the test ends,
+// so tests reusing a subnet ID start from the default names
+func forgetParticipantNames(t *testing.T, subnetID string) {
+	t.Cleanup(func() {
+		participantNamesMu.Lock()
+		defer participantNamesMu.Unlock()
+		delete(participantNames, subnetID)
+	})
+}
+
+func TestParticipantIDsAssignedAtConstruction(t *testing.T) {
+	forgetParticipantNames(t, "subnet-ids")
+	validator := NewCoreValidator("validator-7", "subnet-ids", ConsensusValidator, 1.0, 7)
+	validator.IncrementValidatorClock()
+
+	if got := validator.GetLastMinerClock().Values; len(got) != 1 || got[7] != 1 {
+		t.Errorf("validator clock = %v, want only participant 7 at 1", got)
+	}
+	if got := getParticipantName("subnet-ids", 7); got != "validator-7" {
+		t.Errorf("participant 7 name = %q, want validator-7", got)
+	}
+
+	// A later registration does not rename an existing participant
+	NewCoreMiner("miner-7", "subnet-ids", 7)
+	if got := getParticipantName("subnet-ids", 7); got != "validator-7" {
+		t.Errorf("participant 7 renamed to %q", got)
+	}
+}
+
+func TestParticipantNamesAreScopedBySubnet(t *testing.T) {
+	forgetParticipantNames(t, "subnet-north")
+	forgetParticipantNames(t, "subnet-south")
+	NewCoreValidator("north-auditor", "subnet-north", ConsensusValidator, 1.0, 3)
+	NewCoreValidator("south-auditor", "subnet-south", ConsensusValidator, 1.0, 3)
+	NewCoreMiner("miner-1", "subnet-north", 1)
+
+	tests := []struct {
+		subnetID string
+		id       uint64
+		want     string
+	}{
+		{"subnet-north", 3, "north-auditor"},
+		{"subnet-south", 3, "south-auditor"},
+		{"subnet-other", 3, "Participant-3"},
+		{"subnet-north", 1, "Miner"}, // Default names are kept
+		{"subnet-other", 2, "Validator-1"},
+	}
+	for _, tt := range tests {
+		if got := getParticipantName(tt.subnetID, tt.id); got != tt.want {
+			t.Errorf("getParticipantName(%q, %d) = %q, want %q", tt.subnetID, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeClockDiff(t *testing.T) {
+	tests := []struct {
+		name            string
+		local, incoming *vlc.Clock
+		want            string
+	}{
+		{"same clock", clockOfValues(map[uint64]uint64{1: 2}), clockOfValues(map[uint64]uint64{1: 2}), "no participant changed"},
+		{"advanced", clockOfValues(map[uint64]uint64{1: 2, 2: 1}), clockOfValues(map[uint64]uint64{1: 3, 2: 1}), "Miner +1"},
+		// Compare treats a new participant held at zero as a change, so the diff names it
+		{"new participant at zero", clockOfValues(map[uint64]uint64{1: 2}), clockOfValues(map[uint64]uint64{1: 2, 2: 0}), "Validator-1 +0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeClockDiff("subnet-diff", tt.local.Diff(tt.incoming)); got != tt.want {
+				t.Errorf("describeClockDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeValidatorWeights(t *testing.T) {
+	normalized, err := NormalizeValidatorWeights([]ValidatorWeight{
+		{ID: "validator-1", Weight: 5},
+		{ID: "validator-2", Weight: 2},
+		{ID: "validator-3", Weight: 1},
+		{ID: "validator-4", Weight: 0},
+	})
+	if err != nil {
+		t.Fatalf("NormalizeValidatorWeights() error: %v", err)
+	}
+
+	want := []float64{0.625, 0.25, 0.125, 0}
+	for i, vw := range normalized {
+		if math.Abs(vw.Weight-want[i]) > 1e-9 {
+			t.Errorf("%s weight = %v, want %v", vw.ID, vw.Weight, want[i])
+		}
+	}
+}
+
+func TestNormalizeValidatorWeightsRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []ValidatorWeight
+	}{
+		{"empty", nil},
+		{"negative", []ValidatorWeight{{ID: "validator-1", Weight: 2}, {ID: "validator-2", Weight: -1}}},
+		{"all zero", []ValidatorWeight{{ID: "validator-1", Weight: 0}}},
+		{"duplicate ID", []ValidatorWeight{{ID: "validator-1", Weight: 1}, {ID: "validator-1", Weight: 1}}},
+		{"missing ID", []ValidatorWeight{{Weight: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NormalizeValidatorWeights(tt.weights); err == nil {
+				t.Errorf("NormalizeValidatorWeights(%v) accepted invalid weights", tt.weights)
+			}
+		})
+	}
+}
+
+func TestLoadValidatorWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+	content := `[{"id": "validator-1", "weight": 3}, {"id": "validator-2", "weight": 1}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	weights, err := LoadValidatorWeights(path)
+	if err != nil {
+		t.Fatalf("LoadValidatorWeights() error: %v", err)
+	}
+	if len(weights) != 2 || weights[0] != (ValidatorWeight{ID: "validator-1", Weight: 3}) || weights[1] != (ValidatorWeight{ID: "validator-2", Weight: 1}) {
+		t.Errorf("LoadValidatorWeights() = %v", weights)
+	}
+}
+
+func TestUpdateMinerClockMaxJump(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxJump uint64
+		miner   uint64 // Incoming miner clock value; the validator last saw 5
+		wantErr bool
+	}{
+		{"legitimate +1", 10, 6, false},
+		{"jump at the limit", 10, 15, false},
+		{"abusive large jump", 10, 1000000, true},
+		{"no limit", 0, 1000000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewCoreValidator("validator-1", "subnet-jump", UserInterfaceValidator, 1.0, 2)
+			validator.MaxClockJump = tt.maxJump
+			if err := validator.UpdateMinerClock(clockOfValues(map[uint64]uint64{1: 5, 2: 3})); err != nil {
+				t.Fatal(err)
+			}
+
+			err := validator.UpdateMinerClock(clockOfValues(map[uint64]uint64{1: tt.miner, 2: 3}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateMinerClock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := tt.miner
+			if tt.wantErr {
+				want = 5 // A rejected clock is not merged
+			}
+			if got := validator.GetLastMinerClock().Values[1]; got != want {
+				t.Errorf("miner clock value = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdateValidatorClockMaxJump(t *testing.T) {
+	miner := NewCoreMiner("miner-1", "subnet-jump", 1)
+	miner.MaxClockJump = 10
+
+	if err := miner.UpdateValidatorClock(clockOfValues(map[uint64]uint64{2: 1})); err != nil {
+		t.Fatalf("legitimate +1 merge rejected: %v", err)
+	}
+	if err := miner.UpdateValidatorClock(clockOfValues(map[uint64]uint64{2: 500})); err == nil {
+		t.Fatal("abusive large jump was merged")
+	}
+	if got := miner.GetCurrentClock().Values[2]; got != 1 {
+		t.Errorf("validator clock value = %d, want 1", got)
+	}
+}
+
+func TestBootstrapPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BootstrapPolicy
+		clock  map[uint64]uint64
+		want   bool
+	}{
+		{"accept-any legitimate", AcceptAnyBootstrap, map[uint64]uint64{1: 1, 2: 1}, true},
+		{"accept-any inflated", AcceptAnyBootstrap, map[uint64]uint64{1: 1000000}, true},
+		{"accept-zero-only legitimate", AcceptZeroOnlyBootstrap, map[uint64]uint64{1: 1, 2: 1}, true},
+		{"accept-zero-only inflated sender", AcceptZeroOnlyBootstrap, map[uint64]uint64{1: 1000000}, false},
+		{"accept-zero-only inflated peers", AcceptZeroOnlyBootstrap, map[uint64]uint64{1: 1, 2: 1000000, 99: 7}, false},
+		{"require-preauthorized legitimate", RequirePreauthorizedBootstrap, map[uint64]uint64{1: 1, 2: 1}, true},
+		{"require-preauthorized unexpected start", RequirePreauthorizedBootstrap, map[uint64]uint64{1: 5}, false},
+		{"require-preauthorized inflated peers", RequirePreauthorizedBootstrap, map[uint64]uint64{1: 1, 2: 1000000, 99: 7}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewCoreValidator("validator-1", "subnet-bootstrap", UserInterfaceValidator, 1.0, 2)
+			validator.BootstrapPolicy = tt.policy
+			validator.AuthorizeBootstrap(1, 1)
+			validator.IncrementValidatorClock() // The validator has seen its own first step
+
+			if got := validator.ValidateSequence(clockOfValues(tt.clock), 1); got != tt.want {
+				t.Fatalf("ValidateSequence(%v) = %v, want %v", tt.clock, got, tt.want)
+			}
+			if !tt.want {
+				if got := validator.GetLastMinerClock(); !got.Equal(clockOfValues(map[uint64]uint64{2: 1})) {
+					t.Errorf("rejected bootstrap changed the clock to %s", got)
+				}
+			}
+		})
+	}
+}
+
+func TestRequirePreauthorizedBootstrapRejectsUnknownSender(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-bootstrap", UserInterfaceValidator, 1.0, 2)
+	validator.BootstrapPolicy = RequirePreauthorizedBootstrap
+	validator.AuthorizeBootstrap(1, 1)
+
+	if validator.ValidateSequence(clockOfValues(map[uint64]uint64{3: 1}), 3) {
+		t.Error("ValidateSequence() bootstrapped a sender missing from the allowlist")
+	}
+}
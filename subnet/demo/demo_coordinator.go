@@ -11,7 +11,11 @@
 package demo
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hetu-project/Intelligence-KEY-Mining/subnet"
@@ -25,35 +29,67 @@ import (
 // Architecture:
 //   - Uses 1 miner with DemoTaskProcessor (hardcoded AI responses)
 //   - Uses 4 validators with DemoQualityAssessor and DemoUserInteractionHandler
-//   - Processes 7 predefined inputs with known expected outcomes
+//   - Processes 7 predefined inputs by default (replaceable via SetInputs/LoadInputsFromFile)
 //   - Demonstrates both normal processing and info request scenarios
 type DemoCoordinator struct {
 	SubnetID     string                    // Unique identifier for this demo subnet
 	Miner        *subnet.CoreMiner         // AI agent processing tasks
 	Validators   []*subnet.CoreValidator   // Quality assessment and consensus nodes
 	userInputs   []string                  // Predefined demo inputs for consistent testing
+	requestBase  int                       // Request number preceding the current run's first input
 	GraphAdapter *subnet.SubnetGraphAdapter // Graph adapter for VLC event visualization
+	TieBreak     subnet.TieBreakPolicy      // How an exact 50/50 validator split is decided
+	MinVotes     int                        // Minimum validator votes for a valid consensus (0 = no minimum)
+	QuorumWeight float64                    // Minimum voting weight for a valid consensus (0 = no minimum)
+
+	MaxInfoRequests int           // Info requests allowed per round before it fails (0 = DefaultMaxInfoRequests)
+	InputDelay      time.Duration // Pause between inputs in RunDemo, for readable output
 }
 
-// NewDemoCoordinator creates a new demo coordinator with all PoC-specific logic
-func NewDemoCoordinator(subnetID string) *DemoCoordinator {
+// DefaultMaxInfoRequests bounds clarification loops from processors that keep asking for more info
+const DefaultMaxInfoRequests = 3
+
+// NewDemoCoordinator creates a new demo coordinator with all PoC-specific logic.
+// An optional GenesisConfig customizes the subnet's genesis event.
+func NewDemoCoordinator(subnetID string, genesis ...subnet.GenesisConfig) *DemoCoordinator {
+	// Equal weights for 4 validators
+	weights := make([]subnet.ValidatorWeight, 4)
+	for i := range weights {
+		weights[i] = subnet.ValidatorWeight{ID: fmt.Sprintf("validator-%d", i+1), Weight: 0.25}
+	}
+
+	dc, _ := NewDemoCoordinatorWithWeights(subnetID, weights, genesis...) // Default weights are always valid
+	return dc
+}
+
+// NewDemoCoordinatorWithWeights creates a demo coordinator whose validators and voting
+// weights come from configuration. Weights are normalized to sum to 1.0; the first
+// validator takes the user interface role. An optional GenesisConfig customizes the
+// subnet's genesis event, and every participant's clock starts from its InitialClock.
+func NewDemoCoordinatorWithWeights(subnetID string, weights []subnet.ValidatorWeight, genesis ...subnet.GenesisConfig) (*DemoCoordinator, error) {
+	normalized, err := subnet.NormalizeValidatorWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create core miner with demo task processor
-	miner := subnet.NewCoreMiner("miner-1", subnetID)
+	miner := subnet.NewCoreMiner("miner-1", subnetID, 1) // VLC participant 1
 	miner.SetTaskProcessor(NewDemoTaskProcessor())
 
 	// Create core validators with demo plugins
-	validators := make([]*subnet.CoreValidator, 4)
-	for i := 0; i < 4; i++ {
+	validators := make([]*subnet.CoreValidator, len(normalized))
+	for i, vw := range normalized {
 		role := subnet.ConsensusValidator
 		if i == 0 {
 			role = subnet.UserInterfaceValidator // First validator handles user interaction
 		}
 
 		validator := subnet.NewCoreValidator(
-			fmt.Sprintf("validator-%d", i+1),
+			vw.ID,
 			subnetID,
 			role,
-			0.25, // Equal weights for 4 validators
+			vw.Weight,
+			uint64(i+2), // VLC participant IDs follow the miner: Validator-1 = 2, ...
 		)
 
 		// Set demo-specific plugins
@@ -64,13 +100,15 @@ func NewDemoCoordinator(subnetID string) *DemoCoordinator {
 	}
 
 	// Create graph adapter for visualization
-	graphAdapter := subnet.NewSubnetGraphAdapter(subnetID, 1, "subnet-coordinator")
-
-	return &DemoCoordinator{
-		SubnetID:     subnetID,
-		Miner:        miner,
-		Validators:   validators,
-		GraphAdapter: graphAdapter,
+	graphAdapter := subnet.NewSubnetGraphAdapter(subnetID, 1, "subnet-coordinator", genesis...)
+
+	dc := &DemoCoordinator{
+		SubnetID:        subnetID,
+		Miner:           miner,
+		Validators:      validators,
+		GraphAdapter:    graphAdapter,
+		MaxInfoRequests: DefaultMaxInfoRequests,
+		InputDelay:      1 * time.Second,
 		userInputs: []string{
 			"Analyze market trends for Q4",
 			"Generate summary report for project Alpha",
@@ -81,10 +119,125 @@ func NewDemoCoordinator(subnetID string) *DemoCoordinator {
 			"Provide comprehensive analysis of system architecture",
 		},
 	}
+	dc.SeedClocksFromGraph()
+	return dc, nil
+}
+
+// SeedClocksFromGraph merges the graph's latest VLC clock into the miner and every
+// validator, so their next events order after the genesis or restored graph state
+func (dc *DemoCoordinator) SeedClocksFromGraph() {
+	clock := dc.GraphAdapter.LatestClock()
+	dc.Miner.SeedClock(clock)
+	for _, validator := range dc.Validators {
+		validator.SeedClock(clock)
+	}
+}
+
+// ResumeFromStore restores the graph adapter from its state store and seeds every
+// participant's clock from the restored graph, so resumed rounds order after the
+// saved ones. Returns subnet.ErrNoSavedState if the store holds no state yet.
+func (dc *DemoCoordinator) ResumeFromStore() error {
+	if err := dc.GraphAdapter.LoadStateFromStore(); err != nil {
+		return err
+	}
+	dc.SeedClocksFromGraph()
+	return nil
+}
+
+// SetInputs replaces the demo inputs processed by RunDemo
+func (dc *DemoCoordinator) SetInputs(inputs []string) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("demo inputs must not be empty")
+	}
+	dc.userInputs = append([]string(nil), inputs...)
+	return nil
+}
+
+// LoadInputsFromFile loads demo inputs from a file containing either a JSON
+// array of strings or one input per line (blank lines are ignored)
+func (dc *DemoCoordinator) LoadInputsFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read demo inputs: %v", err)
+	}
+
+	var inputs []string
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &inputs); err != nil {
+			return fmt.Errorf("failed to parse demo inputs JSON: %v", err)
+		}
+	} else {
+		for _, line := range strings.Split(trimmed, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+	}
+
+	return dc.SetInputs(inputs)
 }
 
 // RunDemo executes the complete demo scenario using the separated core/demo architecture
 func (dc *DemoCoordinator) RunDemo() {
+	if len(dc.userInputs) == 0 {
+		fmt.Printf("No demo inputs configured - nothing to run\n")
+		return
+	}
+
+	dc.printHeader()
+	dc.requestBase = dc.GraphAdapter.ReserveRequestNumbers(len(dc.userInputs))
+
+	// Process each input according to demo scenario
+	for inputNum := 1; inputNum <= len(dc.userInputs); inputNum++ {
+		fmt.Printf("--- Processing Input %d ---\n", inputNum)
+		dc.processInput(inputNum, dc.userInputs[inputNum-1])
+		fmt.Println()
+		time.Sleep(dc.InputDelay) // Small delay for readability
+	}
+
+	dc.finishDemo()
+}
+
+// RunDemoConcurrent executes the demo inputs with up to parallelism rounds in flight,
+// for stress-testing the subnet's shared state. Round and epoch tracking stays
+// consistent, but interleaved rounds may log VLC sequence gaps because the +1 rule
+// assumes a single miner operation between validations.
+func (dc *DemoCoordinator) RunDemoConcurrent(parallelism int) {
+	if len(dc.userInputs) == 0 {
+		fmt.Printf("No demo inputs configured - nothing to run\n")
+		return
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	dc.printHeader()
+	dc.requestBase = dc.GraphAdapter.ReserveRequestNumbers(len(dc.userInputs))
+	fmt.Printf("Processing %d inputs with parallelism %d\n\n", len(dc.userInputs), parallelism)
+
+	inputNumbers := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for inputNum := range inputNumbers {
+				fmt.Printf("--- Processing Input %d ---\n", inputNum)
+				dc.processInput(inputNum, dc.userInputs[inputNum-1])
+			}
+		}()
+	}
+	for inputNum := 1; inputNum <= len(dc.userInputs); inputNum++ {
+		inputNumbers <- inputNum
+	}
+	close(inputNumbers)
+	wg.Wait()
+
+	dc.finishDemo()
+}
+
+// printHeader prints the subnet participants before a demo run
+func (dc *DemoCoordinator) printHeader() {
 	fmt.Printf("=== Starting Demo with Refactored Architecture ===\n")
 	fmt.Printf("Subnet ID: %s\n", dc.SubnetID)
 	fmt.Printf("Miner: %s\n", dc.Miner.ID)
@@ -94,15 +247,10 @@ func (dc *DemoCoordinator) RunDemo() {
 	}
 	fmt.Printf("Graph Adapter: Enabled for VLC event visualization\n")
 	fmt.Printf("\n")
+}
 
-	// Process each input according to demo scenario
-	for inputNum := 1; inputNum <= 7; inputNum++ {
-		fmt.Printf("--- Processing Input %d ---\n", inputNum)
-		dc.processInput(inputNum, dc.userInputs[inputNum-1])
-		fmt.Println()
-		time.Sleep(1 * time.Second) // Small delay for readability
-	}
-
+// finishDemo prints the final summary and commits the event graph to Dgraph
+func (dc *DemoCoordinator) finishDemo() {
 	// Print final summary
 	dc.printSummary()
 	
@@ -129,7 +277,7 @@ func (dc *DemoCoordinator) RunDemo() {
 
 // processInput handles a single user input through the complete round-based workflow with VLC
 func (dc *DemoCoordinator) processInput(inputNumber int, input string) {
-	requestID := fmt.Sprintf("req-%s-%d", dc.SubnetID, inputNumber)
+	requestID := fmt.Sprintf("req-%s-%d", dc.SubnetID, dc.requestBase+inputNumber) // Unique across resumed runs
 
 	fmt.Printf("User Input: %s\n", input)
 
@@ -143,7 +291,7 @@ func (dc *DemoCoordinator) processInput(inputNumber int, input string) {
 
 	// Step 1: Miner processes input (Miner VLC will increment)
 	// Sync miner's clock with validator's current state first
-	dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
+	dc.syncMinerClock(uiValidator.GetLastMinerClock())
 	minerResponse := dc.Miner.ProcessInput(input, inputNumber, requestID) // Miner VLC{1:++}
 
 	// Track miner's response (output or info request)
@@ -158,54 +306,110 @@ func (dc *DemoCoordinator) processInput(inputNumber int, input string) {
 	}
 }
 
-// handleInfoRequest processes the scenario where miner needs more information with VLC orchestration
+// handleInfoRequest processes the scenario where miner needs more information with VLC orchestration.
+// Processors implementing subnet.FollowUpTaskProcessor may ask again after each answer;
+// the round fails once the miner asks more than MaxInfoRequests times.
 func (dc *DemoCoordinator) handleInfoRequest(inputNumber int, originalInput string, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
-	fmt.Printf("Miner requests more info: %s\n", minerResponse.InfoRequest)
+	uiValidator := dc.Validators[0]
+	var answers []string
 
-	// Step 1: Validate miner's VLC sequence (NeedMoreInfo message)
-	dc.validateVLCSequenceFromMiner(minerResponse)
+	// Clarification is always bounded, so a processor that keeps asking cannot stall the round
+	maxInfoRequests := dc.MaxInfoRequests
+	if maxInfoRequests <= 0 {
+		maxInfoRequests = DefaultMaxInfoRequests
+	}
 
-	// Step 2: UI Validator orchestrates info request
-	uiValidator := dc.Validators[0]
-	
-	// Update UI validator's VLC with miner's latest state
-	uiValidator.UpdateMinerClock(minerResponse.VLCClock)
-	
-	infoRequest := uiValidator.RequestMoreInfo(minerResponse.RequestID, minerResponse.InfoRequest)
+	for infoRequests := 1; ; infoRequests++ {
+		fmt.Printf("Miner requests more info: %s\n", minerResponse.InfoRequest)
+
+		// Step 1: Validate miner's VLC sequence (NeedMoreInfo message)
+		dc.validateVLCSequenceFromMiner(minerResponse)
+
+		// Step 2: UI Validator updates its VLC with miner's latest state and orchestrates the info request
+		if err := uiValidator.UpdateMinerClock(minerResponse.VLCClock); err != nil {
+			fmt.Printf("ERROR: Validator-1 rejected miner clock: %v\n", err)
+			dc.failRound(inputNumber, minerResponse.RequestID, "No user feedback (miner clock rejected)", "ROUND FAILED: MINER CLOCK REJECTED", parentEventID)
+			return
+		}
+
+		if infoRequests > maxInfoRequests {
+			fmt.Printf("ERROR: Miner exceeded %d info requests for input %d\n", maxInfoRequests, inputNumber)
+			dc.failRound(inputNumber, minerResponse.RequestID, "No user feedback (info request limit exceeded)", "ROUND FAILED: INFO REQUEST LIMIT EXCEEDED", parentEventID)
+			return
+		}
+
+		infoRequest := uiValidator.RequestMoreInfo(minerResponse.RequestID, minerResponse.InfoRequest)
+		if infoRequest == nil {
+			return
+		}
 
-	if infoRequest != nil {
 		fmt.Printf("Validator %s asks user: %s\n", uiValidator.ID, infoRequest.Question)
 
+		// Step 3: Obtain the user's additional context through the interaction handler
+		additionalInfo, err := uiValidator.ProvideAdditionalInfo(infoRequest.RequestID, infoRequest.Question)
+		if err != nil {
+			fmt.Printf("ERROR: Could not obtain additional info: %v\n", err)
+			dc.failRound(inputNumber, minerResponse.RequestID, "No user feedback (additional info unavailable)", "ROUND FAILED: ADDITIONAL INFO UNAVAILABLE", parentEventID)
+			return
+		}
+
 		// *** Validator-1 VLC increment for processing user's additional info ***
 		uiValidator.IncrementValidatorClock() // Validator-1 VLC{2:++}
 		fmt.Printf("Validator-1: Incremented VLC for processing user's additional context\n")
 
-		// Step 3: Simulate user providing additional info based on demo scenario
-		var additionalInfo string
-		switch inputNumber {
-		case 3:
-			additionalInfo = "Focus on cost optimization and ROI analysis specifically."
-		case 6:
-			additionalInfo = "Use REST API with JSON payloads, authentication via OAuth 2.0."
-		}
-
 		fmt.Printf("User provides: %s\n", additionalInfo)
 
 		// Track validator VLC increment for processing additional info
 		infoResponseEventID := dc.GraphAdapter.TrackInfoResponse(minerResponse.RequestID, additionalInfo, uiValidator.GetLastMinerClock(), parentEventID)
 
-		// Step 4: Sync miner with validator's updated VLC state and process additional info
-		dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
-		finalResponse := dc.Miner.ProcessAdditionalInfo(originalInput, additionalInfo, inputNumber, minerResponse.RequestID) // Miner VLC{1:++}
+		// Step 4: Sync miner with validator's updated VLC state and process all context gathered so far
+		answers = append(answers, additionalInfo)
+		dc.syncMinerClock(uiValidator.GetLastMinerClock())
+		finalResponse := dc.Miner.ProcessAdditionalInfo(originalInput, strings.Join(answers, "\n"), inputNumber, minerResponse.RequestID) // Miner VLC{1:++}
 
 		// Track miner VLC increment for final processing
 		finalProcessEventID := dc.GraphAdapter.TrackMinerResponse(minerResponse.RequestID, finalResponse, infoResponseEventID)
 
-		// Step 5: Handle final output with quality voting
-		dc.handleNormalOutput(inputNumber, finalResponse, finalProcessEventID)
+		if finalResponse.OutputType != subnet.NeedMoreInfo {
+			// Step 5: Handle final output with quality voting
+			dc.handleNormalOutput(inputNumber, finalResponse, finalProcessEventID)
+			return
+		}
+
+		// The miner asked another question; repeat the exchange
+		minerResponse = finalResponse
+		parentEventID = finalProcessEventID
 	}
 }
 
+// failRound ends a round that could not reach quality voting, recording it as failed
+// with the same round-end VLC increment and graph tracking as a normal round
+func (dc *DemoCoordinator) failRound(inputNumber int, requestID string, userFeedback string, finalResult string, parentEventID string) {
+	uiValidator := dc.Validators[0]
+
+	// *** ROUND END: Validator-1 VLC increment for final result aggregation ***
+	uiValidator.IncrementValidatorClock() // Validator-1 VLC{2:++}
+	fmt.Printf("Round %d: Completed by Validator-1 aggregating final result\n", inputNumber)
+
+	dc.GraphAdapter.TrackRoundComplete(
+		requestID,
+		inputNumber,
+		uiValidator.GetLastMinerClock(),
+		"NOT EVALUATED",
+		false,
+		userFeedback,
+		false,
+		finalResult,
+		parentEventID,
+	)
+
+	fmt.Printf("Final result: %s\n", finalResult)
+
+	// Sync miner with final validator state
+	dc.syncMinerClock(uiValidator.GetLastMinerClock())
+	fmt.Printf("Round %d: VLC synchronization complete\n", inputNumber)
+}
+
 // validateVLCSequenceFromMiner validates miner's VLC sequence across all validators
 func (dc *DemoCoordinator) validateVLCSequenceFromMiner(minerResponse *subnet.MinerResponseMessage) {
 	fmt.Printf("Validators validating Miner VLC sequence (local verification)...\n")
@@ -216,7 +420,7 @@ func (dc *DemoCoordinator) validateVLCSequenceFromMiner(minerResponse *subnet.Mi
 	for i, validator := range dc.Validators {
 		if i == 0 {
 			// Validator-1 (UI) - full VLC participant
-			if !validator.ValidateSequence(minerResponse.VLCClock, 1) { // Miner ID = 1
+			if !validator.ValidateSequence(minerResponse.VLCClock, dc.Miner.ParticipantID) {
 				fmt.Printf("ERROR: Miner VLC validation failed for %s\n", validator.ID)
 				allValid = false
 			}
@@ -244,10 +448,21 @@ func (dc *DemoCoordinator) validateVLCSequenceFromValidator(validatorClock *vlc.
 	
 	// Miner validates validator's VLC operations
 	// This maintains bidirectional VLC consistency
-	dc.Miner.UpdateValidatorClock(validatorClock)
+	if err := dc.Miner.UpdateValidatorClock(validatorClock); err != nil {
+		fmt.Printf("Validator-1 VLC validation: FAILED (%v)\n", err)
+		return
+	}
 	fmt.Printf("Validator-1 VLC validation: PASSED (miner synchronized)\n")
 }
 
+// syncMinerClock merges Validator-1's clock into the miner's, logging a rejected merge.
+// A rejected clock leaves the miner behind, so its next message fails VLC validation.
+func (dc *DemoCoordinator) syncMinerClock(validatorClock *vlc.Clock) {
+	if err := dc.Miner.UpdateValidatorClock(validatorClock); err != nil {
+		fmt.Printf("ERROR: Miner rejected Validator-1 clock: %v\n", err)
+	}
+}
+
 // handleNormalOutput processes normal miner output through VLC validation and quality consensus
 func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
 	fmt.Printf("Miner output: %s\n", minerResponse.Output)
@@ -257,11 +472,16 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 
 	// Step 2: UI Validator updates its VLC state with miner's latest
 	uiValidator := dc.Validators[0]
-	uiValidator.UpdateMinerClock(minerResponse.VLCClock)
+	if err := uiValidator.UpdateMinerClock(minerResponse.VLCClock); err != nil {
+		fmt.Printf("ERROR: Validator-1 rejected miner clock: %v\n", err)
+		dc.failRound(inputNumber, minerResponse.RequestID, "No user feedback (miner clock rejected)", "ROUND FAILED: MINER CLOCK REJECTED", parentEventID)
+		return
+	}
 
 	// Step 3: Create shared quality assessment for consensus voting
 	sharedAssessment := &subnet.QualityAssessment{
 		RequestID: minerResponse.RequestID,
+		TieBreak:  dc.TieBreak,
 	}
 
 	// Step 4: All validators vote on output quality (distributed consensus)
@@ -275,7 +495,7 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 		if vote != nil {
 			votes = append(votes, vote)
 			// Add each validator's vote to the shared assessment
-			sharedAssessment.AddVote(vote.Weight, vote.Accept)
+			sharedAssessment.AddQualityVote(vote.Weight, vote.Accept, vote.Quality)
 		} else {
 			fmt.Printf("ERROR: Validator %s failed to generate vote\n", validator.ID)
 		}
@@ -283,11 +503,26 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 
 	// Step 5: Check consensus using the shared assessment
 	var consensusResult string
+	var consensusAccepted bool
 	var userAccepts bool
 	var userFeedback string
 	var finalResult string
 
-	if sharedAssessment.IsAccepted() {
+	if sharedAssessment.IsTie() {
+		fmt.Printf("Validator vote tied - applying %s policy\n", tieBreakName(dc.TieBreak))
+	}
+
+	if !sharedAssessment.HasQuorum(dc.MinVotes, dc.QuorumWeight) {
+		// Too few validators voted for the outcome to count either way
+		consensusResult = fmt.Sprintf("NO QUORUM (%d/%d votes, %.2f/%.2f weight)",
+			sharedAssessment.VoteCount, dc.MinVotes, sharedAssessment.TotalWeight, dc.QuorumWeight)
+		fmt.Printf("Validator consensus: %s\n", consensusResult)
+
+		userAccepts = false
+		userFeedback = "No user feedback (no quorum)"
+		finalResult = "ROUND FAILED: NO QUORUM"
+	} else if sharedAssessment.IsAccepted() {
+		consensusAccepted = true
 		consensusResult = fmt.Sprintf("ACCEPTED (%.2f/%.2f weight)", sharedAssessment.AcceptVotes, sharedAssessment.TotalWeight)
 		fmt.Printf("Validator consensus: %s\n", consensusResult)
 
@@ -319,6 +554,7 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 		inputNumber, 
 		uiValidator.GetLastMinerClock(), 
 		consensusResult, 
+		consensusAccepted,
 		userFeedback, 
 		userAccepts, 
 		finalResult, 
@@ -328,27 +564,35 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 	fmt.Printf("Final result: %s\n", finalResult)
 	
 	// Sync miner with final validator state
-	dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
+	dc.syncMinerClock(uiValidator.GetLastMinerClock())
 	fmt.Printf("Round %d: VLC synchronization complete\n", inputNumber)
 }
 
+// tieBreakName returns the policy name, treating the zero value as the default policy
+func tieBreakName(policy subnet.TieBreakPolicy) subnet.TieBreakPolicy {
+	if policy == "" {
+		return subnet.RejectOnTie
+	}
+	return policy
+}
+
 // printSummary prints the final state of the subnet
 func (dc *DemoCoordinator) printSummary() {
 	fmt.Printf("=== Demo Summary (Refactored Architecture) ===\n")
 	minerClock := dc.Miner.GetCurrentClock()
-	fmt.Printf("Miner final VLC Clock: %v\n", minerClock.Values)
+	fmt.Printf("Miner final VLC Clock: %s\n", minerClock)
 
 	fmt.Printf("\nValidator final states:\n")
 	for _, validator := range dc.Validators {
 		validatorClock := validator.GetLastMinerClock()
-		fmt.Printf("  %s: Last miner clock = %v\n", validator.ID, validatorClock.Values)
+		fmt.Printf("  %s: Last miner clock = %s\n", validator.ID, validatorClock)
 	}
 
 	fmt.Printf("\nProcessed inputs summary:\n")
 	processedInputs := dc.Miner.GetProcessedInputs()
-	for i := 1; i <= 7; i++ {
+	for i := 1; i <= len(dc.userInputs); i++ {
 		if response, exists := processedInputs[i]; exists {
-			fmt.Printf("  Input %d: Clock=%v, Type=%s\n", i, response.VLCClock.Values, response.OutputType)
+			fmt.Printf("  Input %d: Clock=%s, Type=%s\n", i, response.VLCClock, response.OutputType)
 		}
 	}
 
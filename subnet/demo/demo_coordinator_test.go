@@ -0,0 +1,513 @@
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/subnet"
+)
+
+// newTestCoordinator creates a demo coordinator that runs inputs without pauses
+func newTestCoordinator(t *testing.T, subnetID string) *DemoCoordinator {
+	t.Helper()
+	dc := NewDemoCoordinator(subnetID)
+	dc.InputDelay = 0
+	return dc
+}
+
+// processedInputs returns the user inputs recorded in the coordinator's event graph
+func processedInputs(dc *DemoCoordinator) []string {
+	var inputs []string
+	for _, event := range dc.GraphAdapter.EventGraph.PendingEvents() {
+		if event.Name == "UserInput" {
+			inputs = append(inputs, strings.TrimPrefix(event.Value, "User submits: "))
+		}
+	}
+	return inputs
+}
+
+func TestLoadInputsFromFileProcessesEachInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"newline-delimited", "Summarize the logs\n\n  Draft a reply  \nList open issues\n"},
+		{"json", `["Summarize the logs", "Draft a reply", "List open issues"]`},
+	}
+	want := []string{"Summarize the logs", "Draft a reply", "List open issues"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inputs")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			dc := newTestCoordinator(t, "subnet-inputs")
+			if err := dc.LoadInputsFromFile(path); err != nil {
+				t.Fatalf("LoadInputsFromFile() error: %v", err)
+			}
+			dc.RunDemo()
+
+			got := processedInputs(dc)
+			if strings.Join(got, "|") != strings.Join(want, "|") {
+				t.Errorf("processed inputs = %q, want %q", got, want)
+			}
+			if stats := dc.GraphAdapter.GetSubnetStats(); stats.RequestsProcessed != len(want) {
+				t.Errorf("RequestsProcessed = %d, want %d", stats.RequestsProcessed, len(want))
+			}
+		})
+	}
+}
+
+func TestLoadInputsFromFileRejectsEmptyInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs")
+	if err := os.WriteFile(path, []byte("\n  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dc := newTestCoordinator(t, "subnet-inputs")
+	if err := dc.LoadInputsFromFile(path); err == nil {
+		t.Fatal("LoadInputsFromFile() accepted a file with no inputs")
+	}
+	if err := dc.SetInputs(nil); err == nil {
+		t.Fatal("SetInputs(nil) accepted no inputs")
+	}
+}
+
+// scriptedInfoHandler answers info requests with a fixed reply or error and keeps
+// the demo's feedback on outputs
+type scriptedInfoHandler struct {
+	*DemoUserInteractionHandler
+	answer    string
+	err       error
+	questions []string
+}
+
+func (h *scriptedInfoHandler) ProvideAdditionalInfo(requestID, question string) (string, error) {
+	h.questions = append(h.questions, question)
+	return h.answer, h.err
+}
+
+// roundResults runs the coordinator's inputs and returns each round's result by round number
+func roundResults(dc *DemoCoordinator) map[int]subnet.RoundResult {
+	results := make(map[int]subnet.RoundResult)
+	dc.GraphAdapter.SetRoundCompletedCallback(func(result subnet.RoundResult) {
+		results[result.RoundNumber] = result
+	})
+	dc.RunDemo()
+	return results
+}
+
+func TestInfoRequestAnsweredByHandler(t *testing.T) {
+	dc := newTestCoordinator(t, "subnet-info")
+	handler := &scriptedInfoHandler{DemoUserInteractionHandler: NewDemoUserInteractionHandler(), answer: "Budget is the main concern."}
+	dc.Validators[0].SetUserInteractionHandler(handler)
+	if err := dc.SetInputs([]string{"first", "second", "third"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := roundResults(dc)
+
+	if len(handler.questions) != 1 || handler.questions[0] != contextQuestion {
+		t.Fatalf("handler questions = %q, want [%q]", handler.questions, contextQuestion)
+	}
+	if result := results[3]; !result.Success {
+		t.Errorf("round 3 = %q (success %v), want a successful round", result.FinalResult, result.Success)
+	}
+	var responses []string
+	for _, event := range dc.GraphAdapter.EventGraph.PendingEvents() {
+		if event.Name == "InfoResponse" {
+			responses = append(responses, event.Value)
+		}
+	}
+	if len(responses) != 1 || !strings.Contains(responses[0], handler.answer) {
+		t.Errorf("InfoResponse events = %q, want one containing %q", responses, handler.answer)
+	}
+}
+
+func TestInfoRequestFailsWhenHandlerErrors(t *testing.T) {
+	dc := newTestCoordinator(t, "subnet-info")
+	handler := &scriptedInfoHandler{DemoUserInteractionHandler: NewDemoUserInteractionHandler(), err: errors.New("user unreachable")}
+	dc.Validators[0].SetUserInteractionHandler(handler)
+	if err := dc.SetInputs([]string{"first", "second", "third"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := roundResults(dc)
+
+	result := results[3]
+	if result.Success || result.FinalResult != "ROUND FAILED: ADDITIONAL INFO UNAVAILABLE" {
+		t.Errorf("round 3 = %q (success %v), want a failed round for unavailable info", result.FinalResult, result.Success)
+	}
+	if !results[1].Success || !results[2].Success {
+		t.Errorf("rounds without info requests failed: %+v", results)
+	}
+}
+
+// fixedAssessor votes the same way on every output
+type fixedAssessor struct {
+	accept bool
+}
+
+func (a fixedAssessor) AssessQuality(response *subnet.MinerResponseMessage) (float64, bool) {
+	if a.accept {
+		return 0.9, true
+	}
+	return 0.2, false
+}
+
+func TestConsensusFollowsValidatorWeights(t *testing.T) {
+	weights := []subnet.ValidatorWeight{
+		{ID: "validator-1", Weight: 5},
+		{ID: "validator-2", Weight: 1},
+		{ID: "validator-3", Weight: 1},
+		{ID: "validator-4", Weight: 1},
+	}
+	tests := []struct {
+		name       string
+		heavyVotes bool // Vote of validator-1; the three light validators vote the opposite way
+		want       bool
+	}{
+		{"heavy validator accepts", true, true},
+		{"heavy validator rejects", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc, err := NewDemoCoordinatorWithWeights("subnet-weights", weights)
+			if err != nil {
+				t.Fatalf("NewDemoCoordinatorWithWeights() error: %v", err)
+			}
+			dc.InputDelay = 0
+			for i, validator := range dc.Validators {
+				validator.SetQualityAssessor(fixedAssessor{accept: (i == 0) == tt.heavyVotes})
+			}
+			if err := dc.SetInputs([]string{"first"}); err != nil {
+				t.Fatal(err)
+			}
+
+			result := roundResults(dc)[1]
+			if result.ConsensusAccepted != tt.want {
+				t.Errorf("consensus accepted = %v (%s), want %v", result.ConsensusAccepted, result.ConsensusResult, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDemoCoordinatorRejectsNegativeWeight(t *testing.T) {
+	_, err := NewDemoCoordinatorWithWeights("subnet-weights", []subnet.ValidatorWeight{
+		{ID: "validator-1", Weight: 1},
+		{ID: "validator-2", Weight: -0.5},
+	})
+	if err == nil {
+		t.Fatal("NewDemoCoordinatorWithWeights() accepted a negative weight")
+	}
+}
+
+func TestFailingModelEndpointEndsRoundAtInfoRequestLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dc := newTestCoordinator(t, "subnet-http")
+	dc.Miner.SetTaskProcessor(subnet.NewHTTPTaskProcessor(subnet.HTTPTaskProcessorConfig{Endpoint: server.URL}))
+	handler := &scriptedInfoHandler{DemoUserInteractionHandler: NewDemoUserInteractionHandler(), answer: "Please try again."}
+	dc.Validators[0].SetUserInteractionHandler(handler)
+	if err := dc.SetInputs([]string{"first"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := roundResults(dc)[1]
+	if result.Success || result.FinalResult != "ROUND FAILED: INFO REQUEST LIMIT EXCEEDED" {
+		t.Errorf("round = %q (success %v), want a failed round at the info request limit", result.FinalResult, result.Success)
+	}
+	if len(handler.questions) != dc.MaxInfoRequests {
+		t.Errorf("user was asked %d times, want %d", len(handler.questions), dc.MaxInfoRequests)
+	}
+	for _, event := range dc.GraphAdapter.EventGraph.PendingEvents() {
+		if event.Name == "MinerOutput" {
+			t.Errorf("failing endpoint produced a miner output: %s", event.Value)
+		}
+	}
+}
+
+// Run with -race to check the shared subnet state for data races
+func TestRunDemoConcurrentKeepsEpochsConsistent(t *testing.T) {
+	dc := newTestCoordinator(t, "subnet-concurrent")
+	inputs := make([]string, 9)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("Task %d", i+1)
+	}
+	if err := dc.SetInputs(inputs); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	rounds := make(map[string]bool)
+	dc.GraphAdapter.SetRoundCompletedCallback(func(result subnet.RoundResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		rounds[result.RequestID] = true
+	})
+
+	dc.RunDemoConcurrent(4)
+
+	if len(rounds) != len(inputs) {
+		t.Errorf("completed %d rounds, want %d", len(rounds), len(inputs))
+	}
+	stats := dc.GraphAdapter.GetSubnetStats()
+	if stats.CurrentEpoch != 4 || stats.RoundsInEpoch != 0 {
+		t.Errorf("CurrentEpoch/RoundsInEpoch = %d/%d, want 4/0", stats.CurrentEpoch, stats.RoundsInEpoch)
+	}
+	if got := stats.EventsByType["EpochFinalized"]; got != 3 {
+		t.Errorf("EpochFinalized events = %d, want 3", got)
+	}
+	if got := stats.EventsByType["RoundSuccess"] + stats.EventsByType["RoundFailed"]; got != len(inputs) {
+		t.Errorf("round result events = %d, want %d", got, len(inputs))
+	}
+
+	seen := make(map[string]bool)
+	for epoch := 1; epoch <= 3; epoch++ {
+		details, err := dc.GraphAdapter.GetEpochDetails(epoch)
+		if err != nil {
+			t.Fatalf("GetEpochDetails(%d) error: %v", epoch, err)
+		}
+		if len(details.DetailedRounds) != 3 {
+			t.Errorf("epoch %d has %d rounds, want 3", epoch, len(details.DetailedRounds))
+		}
+		for _, round := range details.DetailedRounds {
+			if seen[round.RequestID] {
+				t.Errorf("request %s finalized in more than one epoch", round.RequestID)
+			}
+			seen[round.RequestID] = true
+		}
+	}
+}
+
+func TestQuorumGuardFailsRoundWithMissingVotes(t *testing.T) {
+	tests := []struct {
+		name         string
+		minVotes     int
+		quorumWeight float64
+		wantQuorum   bool
+	}{
+		{"votes below minimum", 3, 0, false},
+		{"weight below quorum", 0, 0.75, false},
+		{"quorum met", 2, 0.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := newTestCoordinator(t, "subnet-quorum")
+			dc.Validators = dc.Validators[:2] // Two of the four validators do not vote
+			dc.MinVotes = tt.minVotes
+			dc.QuorumWeight = tt.quorumWeight
+			if err := dc.SetInputs([]string{"first"}); err != nil {
+				t.Fatal(err)
+			}
+
+			result := roundResults(dc)[1]
+			noQuorum := result.FinalResult == "ROUND FAILED: NO QUORUM"
+			if noQuorum == tt.wantQuorum {
+				t.Errorf("round = %q (consensus %q), want quorum %v", result.FinalResult, result.ConsensusResult, tt.wantQuorum)
+			}
+			if !tt.wantQuorum && (result.Success || result.ConsensusAccepted) {
+				t.Errorf("round without quorum was accepted: %+v", result)
+			}
+		})
+	}
+}
+
+// alwaysAskProcessor asks for more information after every answer
+type alwaysAskProcessor struct {
+	*DemoTaskProcessor
+}
+
+func (alwaysAskProcessor) ProcessTask(input string, inputNumber int) (subnet.MinerOutputType, string, string) {
+	return subnet.NeedMoreInfo, "", "Could you add more detail?"
+}
+
+func (alwaysAskProcessor) ProcessFollowUp(originalInput string, additionalInfo string, inputNumber int) (subnet.MinerOutputType, string, string) {
+	return subnet.NeedMoreInfo, "", "Could you add more detail?"
+}
+
+func TestInfoRequestLimitBoundsEndlessClarification(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		wantAsks int
+	}{
+		{"zero uses default", 0, DefaultMaxInfoRequests},
+		{"negative uses default", -1, DefaultMaxInfoRequests},
+		{"explicit limit", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := newTestCoordinator(t, "subnet-info-limit")
+			dc.MaxInfoRequests = tt.limit
+			dc.Miner.SetTaskProcessor(alwaysAskProcessor{NewDemoTaskProcessor()})
+			handler := &scriptedInfoHandler{DemoUserInteractionHandler: NewDemoUserInteractionHandler(), answer: "More detail."}
+			dc.Validators[0].SetUserInteractionHandler(handler)
+			if err := dc.SetInputs([]string{"first"}); err != nil {
+				t.Fatal(err)
+			}
+
+			result := roundResults(dc)[1]
+			if result.FinalResult != "ROUND FAILED: INFO REQUEST LIMIT EXCEEDED" {
+				t.Errorf("round = %q, want the info request limit failure", result.FinalResult)
+			}
+			if len(handler.questions) != tt.wantAsks {
+				t.Errorf("user was asked %d times, want %d", len(handler.questions), tt.wantAsks)
+			}
+		})
+	}
+}
+
+func TestInfoRequestRoundsRecordQuestionAndFinalOutput(t *testing.T) {
+	dc := newTestCoordinator(t, "subnet-info-rounds")
+	if err := dc.SetInputs([]string{"one", "two", "three", "four", "five", "six"}); err != nil {
+		t.Fatal(err)
+	}
+	results := roundResults(dc)
+
+	tests := []struct {
+		round    int
+		epoch    int
+		question string
+	}{
+		{3, 1, contextQuestion},
+		{6, 2, requirementsQuestion},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("input %d", tt.round), func(t *testing.T) {
+			epoch, err := dc.GraphAdapter.GetEpochDetails(tt.epoch)
+			if err != nil {
+				t.Fatalf("GetEpochDetails(%d) error: %v", tt.epoch, err)
+			}
+			// Epoch round numbers restart each epoch, so match the round by request ID
+			requestID := results[tt.round].RequestID
+			var round *subnet.RoundData
+			for i := range epoch.DetailedRounds {
+				if epoch.DetailedRounds[i].RequestID == requestID {
+					round = &epoch.DetailedRounds[i]
+				}
+			}
+			if round == nil {
+				t.Fatalf("epoch %d has no round for request %q", tt.epoch, requestID)
+			}
+
+			if round.InfoRequest != tt.question || round.InfoRequestCount != 1 || round.InfoResponse == "" {
+				t.Errorf("info request = %q (count %d, response %q), want %q answered once",
+					round.InfoRequest, round.InfoRequestCount, round.InfoResponse, tt.question)
+			}
+			if round.MinerOutput == "" || round.MinerOutputType != "output_ready" {
+				t.Errorf("miner output = %q (%s), want the final output", round.MinerOutput, round.MinerOutputType)
+			}
+			if round.InfoRequestVLC[1] == 0 || round.MinerOutputVLC[1] <= round.InfoRequestVLC[1] {
+				t.Errorf("miner clocks: info request %v, output %v; want the output after the request",
+					round.InfoRequestVLC, round.MinerOutputVLC)
+			}
+
+			// Success is decided by the final output's votes and feedback, not the info request
+			want := round.ConsensusAccepted && round.UserAccept
+			if round.Success != want || results[tt.round].Success != want {
+				t.Errorf("Success = %v (callback %v), want %v from consensus %v and user %v",
+					round.Success, results[tt.round].Success, want, round.ConsensusAccepted, round.UserAccept)
+			}
+		})
+	}
+
+	if !results[3].Success {
+		t.Errorf("round 3 = %q, want a successful round", results[3].FinalResult)
+	}
+	if results[6].Success || !results[6].ConsensusAccepted {
+		t.Errorf("round 6 = %q (consensus %v), want accepted by validators but rejected by the user",
+			results[6].FinalResult, results[6].ConsensusAccepted)
+	}
+}
+
+func TestGenesisClockSeedsParticipants(t *testing.T) {
+	genesis := subnet.GenesisConfig{InitialClock: map[int]int{1: 5, 2: 3}}
+	dc := NewDemoCoordinator("subnet-seeded", genesis)
+	dc.InputDelay = 0
+	if err := dc.SetInputs([]string{"first", "second", "third"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := dc.Miner.GetCurrentClock().String(); got != "{1:5,2:3}" {
+		t.Errorf("miner clock = %s, want the genesis clock", got)
+	}
+	for _, validator := range dc.Validators {
+		if got := validator.GetLastMinerClock().String(); got != "{1:5,2:3}" {
+			t.Errorf("%s clock = %s, want the genesis clock", validator.ID, got)
+		}
+	}
+
+	results := roundResults(dc)
+	for round := 1; round <= 3; round++ {
+		if !results[round].Success {
+			t.Errorf("round %d = %q, want a successful round", round, results[round].FinalResult)
+		}
+	}
+	if _, err := dc.GraphAdapter.TopologicalOrder(); err != nil {
+		t.Errorf("TopologicalOrder() error = %v", err)
+	}
+}
+
+func TestRestoredCoordinatorContinuesRun(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	var requestIDs []string
+	recordRequests := func(dc *DemoCoordinator) {
+		dc.GraphAdapter.SetRoundCompletedCallback(func(result subnet.RoundResult) {
+			requestIDs = append(requestIDs, result.RequestID)
+		})
+	}
+
+	first := newTestCoordinator(t, "subnet-resume")
+	first.GraphAdapter.SetStateStore(subnet.NewFileStateStore(stateFile))
+	if err := first.SetInputs([]string{"one", "two", "three", "four"}); err != nil {
+		t.Fatal(err)
+	}
+	recordRequests(first)
+	first.RunDemo()
+
+	// A restarted coordinator resumes from the saved state and processes more inputs
+	resumed := newTestCoordinator(t, "subnet-resume")
+	resumed.GraphAdapter.SetStateStore(subnet.NewFileStateStore(stateFile))
+	if err := resumed.ResumeFromStore(); err != nil {
+		t.Fatalf("ResumeFromStore() error = %v", err)
+	}
+	if err := resumed.SetInputs([]string{"five", "six"}); err != nil {
+		t.Fatal(err)
+	}
+	recordRequests(resumed)
+	resumed.RunDemo()
+
+	if len(requestIDs) != 6 {
+		t.Fatalf("completed %d rounds, want 6", len(requestIDs))
+	}
+	seen := make(map[string]bool)
+	for _, id := range requestIDs {
+		if seen[id] {
+			t.Errorf("request ID %s reused after resuming", id)
+		}
+		seen[id] = true
+	}
+	if stats := resumed.GraphAdapter.GetSubnetStats(); stats.CurrentEpoch != 3 || stats.RoundsInEpoch != 0 {
+		t.Errorf("CurrentEpoch/RoundsInEpoch = %d/%d, want 3/0", stats.CurrentEpoch, stats.RoundsInEpoch)
+	}
+	if _, err := resumed.GraphAdapter.TopologicalOrder(); err != nil {
+		t.Errorf("TopologicalOrder() error = %v", err)
+	}
+}
@@ -16,6 +16,12 @@ import (
 	"github.com/hetu-project/Intelligence-KEY-Mining/subnet"
 )
 
+// Info request questions asked by the demo miner for inputs 3 and 6
+const (
+	contextQuestion      = "Could you please provide more context about what specific aspect you'd like me to focus on?"
+	requirementsQuestion = "I need clarification on the technical requirements. Could you specify the exact parameters?"
+)
+
 // DemoTaskProcessor implements the TaskProcessor interface with predefined responses
 // for demonstration purposes. Each input number triggers specific behavior patterns:
 //
@@ -38,12 +44,12 @@ func (d *DemoTaskProcessor) ProcessTask(input string, inputNumber int) (subnet.M
 	case 3:
 		// Input 3: Miner requests more info → normal flow
 		fmt.Printf("Miner: Input %d - Requesting more information\n", inputNumber)
-		return subnet.NeedMoreInfo, "", "Could you please provide more context about what specific aspect you'd like me to focus on?"
+		return subnet.NeedMoreInfo, "", contextQuestion
 
 	case 6:
 		// Input 6: Miner requests more info → will eventually be rejected by user
 		fmt.Printf("Miner: Input %d - Requesting more information (will be rejected later)\n", inputNumber)
-		return subnet.NeedMoreInfo, "", "I need clarification on the technical requirements. Could you specify the exact parameters?"
+		return subnet.NeedMoreInfo, "", requirementsQuestion
 
 	default:
 		// Normal processing for inputs 1, 2, 4, 5, 7
@@ -10,6 +10,8 @@
 // feedback collection systems, or user preference learning algorithms.
 package demo
 
+import "fmt"
+
 // DemoUserInteractionHandler simulates realistic user feedback patterns for demonstration.
 // Models different user behavior scenarios to test the complete PoCW workflow:
 //
@@ -49,4 +51,17 @@ func (d *DemoUserInteractionHandler) SimulateUserInteraction(inputNumber int, ou
 		// User accepts satisfactory output (typical positive scenario)
 		return true, "This looks good, thank you!"
 	}
+}
+
+// ProvideAdditionalInfo answers the demo miner's info requests with canned context.
+// Each known question maps to a fixed answer; unknown questions return an error.
+func (d *DemoUserInteractionHandler) ProvideAdditionalInfo(requestID, question string) (string, error) {
+	switch question {
+	case contextQuestion:
+		return "Focus on cost optimization and ROI analysis specifically.", nil
+	case requirementsQuestion:
+		return "Use REST API with JSON payloads, authentication via OAuth 2.0.", nil
+	default:
+		return "", fmt.Errorf("no demo answer for info request on %s", requestID)
+	}
 }
\ No newline at end of file
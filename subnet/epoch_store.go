@@ -0,0 +1,159 @@
+// Package subnet - Finalized Epoch Store
+//
+// This file keeps the full data of every finalized epoch queryable after it has
+// been handed to the bridge or epoch callback. createEpochFinalization writes each
+// EpochData to an EpochStore, and GetEpochDetails reads it back by epoch number.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrEpochNotFound is returned by EpochStore.Load when no epoch with the number has been saved
+var ErrEpochNotFound = errors.New("epoch not found")
+
+// EpochStore persists finalized epochs
+type EpochStore interface {
+	Save(epochData *EpochData) error
+	Load(subnetID string, epochNumber int) (*EpochData, error) // Returns ErrEpochNotFound if the epoch was never saved
+}
+
+// MemoryEpochStore keeps finalized epochs in memory for the life of the process.
+// It is the adapter's default store.
+type MemoryEpochStore struct {
+	mu     sync.RWMutex
+	epochs map[string][]byte // Serialized epochs keyed by subnet and epoch number
+}
+
+// NewMemoryEpochStore creates an empty in-memory epoch store
+func NewMemoryEpochStore() *MemoryEpochStore {
+	return &MemoryEpochStore{epochs: make(map[string][]byte)}
+}
+
+// Save stores a serialized copy of the epoch, so later changes to epochData are not reflected
+func (ms *MemoryEpochStore) Save(epochData *EpochData) error {
+	data, err := json.Marshal(epochData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal epoch %d: %v", epochData.EpochNumber, err)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.epochs[epochKey(epochData.SubnetID, epochData.EpochNumber)] = data
+	return nil
+}
+
+// Load returns a copy of the saved epoch
+func (ms *MemoryEpochStore) Load(subnetID string, epochNumber int) (*EpochData, error) {
+	ms.mu.RLock()
+	data, ok := ms.epochs[epochKey(subnetID, epochNumber)]
+	ms.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrEpochNotFound
+	}
+	return decodeEpoch(data)
+}
+
+// FileEpochStore stores each finalized epoch as a JSON file in a directory,
+// so epoch details remain available across restarts
+type FileEpochStore struct {
+	Dir string
+}
+
+// NewFileEpochStore creates an epoch store backed by the directory, creating it if needed
+func NewFileEpochStore(dir string) (*FileEpochStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create epoch store directory: %v", err)
+	}
+	return &FileEpochStore{Dir: dir}, nil
+}
+
+// Save writes the epoch atomically by replacing its file with a fully written temporary file
+func (fs *FileEpochStore) Save(epochData *EpochData) error {
+	data, err := json.Marshal(epochData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal epoch %d: %v", epochData.EpochNumber, err)
+	}
+
+	path := fs.path(epochData.SubnetID, epochData.EpochNumber)
+	tmp, err := os.CreateTemp(fs.Dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary epoch file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write epoch file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write epoch file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace epoch file: %v", err)
+	}
+	return nil
+}
+
+// Load reads the saved epoch, returning ErrEpochNotFound if its file does not exist
+func (fs *FileEpochStore) Load(subnetID string, epochNumber int) (*EpochData, error) {
+	data, err := os.ReadFile(fs.path(subnetID, epochNumber))
+	if os.IsNotExist(err) {
+		return nil, ErrEpochNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read epoch file: %v", err)
+	}
+	return decodeEpoch(data)
+}
+
+// path returns the file holding the subnet's epoch
+func (fs *FileEpochStore) path(subnetID string, epochNumber int) string {
+	return filepath.Join(fs.Dir, filepath.Base(epochKey(subnetID, epochNumber))+".json")
+}
+
+// epochKey identifies an epoch across subnets sharing a store
+func epochKey(subnetID string, epochNumber int) string {
+	return fmt.Sprintf("%s_epoch_%d", subnetID, epochNumber)
+}
+
+// decodeEpoch unmarshals a saved epoch
+func decodeEpoch(data []byte) (*EpochData, error) {
+	var epochData EpochData
+	if err := json.Unmarshal(data, &epochData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal epoch: %v", err)
+	}
+	return &epochData, nil
+}
+
+// SetEpochStore sets where finalized epochs are saved. The adapter starts with a
+// MemoryEpochStore; use a FileEpochStore to keep epoch details across restarts.
+func (sga *SubnetGraphAdapter) SetEpochStore(store EpochStore) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.epochStore = store
+}
+
+// GetEpochDetails returns the full data of a finalized epoch, including each
+// round's user input, miner output, consensus result and success flag.
+// Returns an error wrapping ErrEpochNotFound if the epoch has not been finalized.
+func (sga *SubnetGraphAdapter) GetEpochDetails(epochNumber int) (*EpochData, error) {
+	sga.mu.RLock()
+	store := sga.epochStore
+	sga.mu.RUnlock()
+
+	if store == nil {
+		return nil, fmt.Errorf("no epoch store configured")
+	}
+	epochData, err := store.Load(sga.SubnetID, epochNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epoch %d: %w", epochNumber, err)
+	}
+	return epochData, nil
+}
@@ -0,0 +1,86 @@
+package subnet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+func TestEpochStores(t *testing.T) {
+	fileStore, err := NewFileEpochStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]EpochStore{"memory": NewMemoryEpochStore(), "file": fileStore}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			epoch := &EpochData{
+				EpochNumber:    1,
+				SubnetID:       "subnet-a",
+				DetailedRounds: []RoundData{{RoundNumber: 1, RequestID: "req-1", Success: true}},
+			}
+			if err := store.Save(epoch); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			epoch.DetailedRounds[0].Success = false // Saved epochs are copies
+
+			loaded, err := store.Load("subnet-a", 1)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(loaded.DetailedRounds) != 1 || loaded.DetailedRounds[0].RequestID != "req-1" || !loaded.DetailedRounds[0].Success {
+				t.Errorf("loaded rounds = %+v, want the saved round", loaded.DetailedRounds)
+			}
+
+			if _, err := store.Load("subnet-a", 2); !errors.Is(err, ErrEpochNotFound) {
+				t.Errorf("Load() of an unsaved epoch error = %v, want ErrEpochNotFound", err)
+			}
+			if _, err := store.Load("subnet-b", 1); !errors.Is(err, ErrEpochNotFound) {
+				t.Errorf("Load() from another subnet error = %v, want ErrEpochNotFound", err)
+			}
+		})
+	}
+}
+
+func TestGetEpochDetailsAfterFinalization(t *testing.T) {
+	store, err := NewFileEpochStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sga := NewSubnetGraphAdapter("subnet-details", 1, "node-1")
+	sga.SetEpochStore(store)
+
+	if _, err := sga.GetEpochDetails(1); !errors.Is(err, ErrEpochNotFound) {
+		t.Fatalf("GetEpochDetails() before finalization error = %v, want ErrEpochNotFound", err)
+	}
+
+	clock := vlc.New()
+	trackRound(t, sga, clock, "req-1", 1, true)
+	trackRound(t, sga, clock, "req-2", 2, false)
+	trackRound(t, sga, clock, "req-3", 3, true)
+
+	// A new adapter sharing the store sees the epoch after a restart
+	restarted := NewSubnetGraphAdapter("subnet-details", 1, "node-1")
+	restarted.SetEpochStore(store)
+	epoch, err := restarted.GetEpochDetails(1)
+	if err != nil {
+		t.Fatalf("GetEpochDetails(1) error = %v", err)
+	}
+
+	if epoch.EpochNumber != 1 || epoch.SubnetID != "subnet-details" || len(epoch.DetailedRounds) != 3 {
+		t.Fatalf("epoch = %d/%s with %d rounds, want 1/subnet-details with 3", epoch.EpochNumber, epoch.SubnetID, len(epoch.DetailedRounds))
+	}
+	for i, round := range epoch.DetailedRounds {
+		wantSuccess := round.RequestID != "req-2"
+		wantConsensus := "ACCEPTED"
+		if !wantSuccess {
+			wantConsensus = "REJECTED"
+		}
+		if round.UserInput != "input for "+round.RequestID || round.MinerOutput != "output for "+round.RequestID ||
+			round.ConsensusResult != wantConsensus || round.Success != wantSuccess {
+			t.Errorf("round %d = %+v", i+1, round)
+		}
+	}
+}
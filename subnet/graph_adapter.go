@@ -7,6 +7,9 @@ package subnet
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,27 +17,50 @@ import (
 	"time"
 
 	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph"
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
 	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
 )
 
 // EpochFinalizedCallback is called when an epoch is finalized
 type EpochFinalizedCallback func(epochNumber int, subnetID string, epochData *EpochData)
 
+// RoundCompletedCallback is called after each round completes and is recorded in the graph
+type RoundCompletedCallback func(roundResult RoundResult)
+
+// RoundResult is the outcome of a completed round delivered to RoundCompletedCallback
+type RoundResult struct {
+	SubnetID          string      `json:"subnetId"`
+	RequestID         string      `json:"requestId"`
+	RoundNumber       int         `json:"roundNumber"`     // Round number as passed to TrackRoundComplete
+	EventID           string      `json:"eventId"`         // RoundSuccess/RoundFailed event for this round
+	ConsensusResult   string      `json:"consensusResult"` // Validator consensus decision
+	ConsensusAccepted bool        `json:"consensusAccepted"`
+	UserFeedback      string      `json:"userFeedback"`
+	UserAccept        bool        `json:"userAccept"`
+	FinalResult       string      `json:"finalResult"`
+	Success           bool        `json:"success"` // Same as RoundData.Success for this round
+	VLCClockState     map[int]int `json:"vlcClockState"`
+}
+
 // RoundData contains detailed information about a single round
 type RoundData struct {
-	RoundNumber     int                 `json:"roundNumber"`
-	RequestID       string              `json:"requestId"`
-	UserInput       string              `json:"userInput"`
-	MinerOutput     string              `json:"minerOutput"`
-	MinerOutputType string              `json:"minerOutputType"`
-	InfoRequest     string              `json:"infoRequest,omitempty"`
-	InfoResponse    string              `json:"infoResponse,omitempty"`
-	ConsensusResult string              `json:"consensusResult"`
-	UserFeedback    string              `json:"userFeedback"`
-	UserAccept      bool                `json:"userAccept"`
-	FinalResult     string              `json:"finalResult"`
-	VLCClockState   map[int]int         `json:"vlcClockState"`
-	Success         bool                `json:"success"`
+	RoundNumber       int         `json:"roundNumber"`
+	RequestID         string      `json:"requestId"`
+	UserInput         string      `json:"userInput"`
+	MinerOutput       string      `json:"minerOutput"`
+	MinerOutputType   string      `json:"minerOutputType"`
+	InfoRequest       string      `json:"infoRequest,omitempty"`      // First clarification question the miner asked
+	InfoResponse      string      `json:"infoResponse,omitempty"`     // User's answer to InfoRequest
+	InfoRequestCount  int         `json:"infoRequestCount,omitempty"` // Clarification questions asked during the round
+	InfoRequestVLC    map[int]int `json:"infoRequestVlc,omitempty"`   // Miner clock when it first asked for more info
+	MinerOutputVLC    map[int]int `json:"minerOutputVlc,omitempty"`   // Miner clock of the final output
+	ConsensusResult   string      `json:"consensusResult"`
+	ConsensusAccepted bool        `json:"consensusAccepted"`
+	UserFeedback      string      `json:"userFeedback"`
+	UserAccept        bool        `json:"userAccept"`
+	FinalResult       string      `json:"finalResult"`
+	VLCClockState     map[int]int `json:"vlcClockState"`
+	Success           bool        `json:"success"` // Whether the round earns rewards; see SetRewardRequiresUserAcceptance
 }
 
 // EpochData contains the data for a completed epoch
@@ -46,6 +72,7 @@ type EpochData struct {
 	VLCClockState     map[int]int         `json:"vlcClockState"`
 	EpochEventID      string              `json:"epochEventId"`
 	ParentRoundEventID string             `json:"parentRoundEventId"`
+	FinalizedAt       int64               `json:"finalizedAt"` // Unix time the epoch was finalized
 }
 
 // SubnetGraphAdapter adapts PoCW subnet events for causal graph visualization.
@@ -63,24 +90,70 @@ type EpochData struct {
 // VLC Integration:
 //   - Each event includes VLC clock state for causal ordering
 //   - Parent-child relationships reflect VLC causality
-//   - Only events from VLC participants (Miner=1, Validator-1=2) have full VLC data
+//   - Only events from VLC participants (the miner and validators) have full VLC data
 type SubnetGraphAdapter struct {
 	EventGraph        *dgraph.EventGraph     // Dgraph event graph for visualization
 	SubnetID          string                 // Subnet identifier
 	mu                sync.RWMutex           // Protects event tracking state
 	roundCounters     map[string]int         // Per-request round counters
+	requestCounter    int                    // Request numbers handed out by ReserveRequestNumbers
 	completedRounds   []string               // Track completed rounds for epoch creation
 	epochCount        int                    // Current epoch number
 	lastEventInChain  string                 // Last event for continuous chaining
 	genesisEventID    string                 // Genesis state event ID
 	roundsInEpoch     int                    // Counter for rounds within current epoch
 	epochCallback     EpochFinalizedCallback // Callback triggered when epoch is finalized
+	roundCallback     RoundCompletedCallback // Callback triggered when a round completes
 	bridgeURL         string                 // URL of the JavaScript bridge service
-	currentRounds     map[string]*RoundData  // Track detailed data for rounds in current epoch
+	bridgeSecret      string                 // Shared secret used to sign epoch payloads sent to the bridge
+	currentRounds     map[string]*RoundData  // Track detailed data for in-flight and completed rounds, keyed by request
+	epochRequests     []string               // Request IDs of rounds completed in the current epoch, in completion order
+	lastVLCState      map[int]int            // VLC state of the most recently tracked event
+	lastEpochAt       time.Time              // When the last epoch was finalized (or the adapter created)
+	stateStore        StateStore             // Where adapter state is saved after each round (nil = not persisted)
+	epochStore        EpochStore             // Where finalized epochs are saved for GetEpochDetails
+	requireUserAccept bool                   // Whether successful (rewarded) rounds also need the user to accept
+	minMiningRounds   int                    // Successful rounds an epoch needs to be submitted for KEY mining (0 = always)
+	clock             Clock                  // Wall-clock time source for epoch timing and timestamps
+	metricsMu         sync.Mutex             // Protects epochMetrics, which submission goroutines update
+	epochMetrics      EpochMetrics           // Epoch submission outcome counters
+}
+
+// EpochMetrics counts epoch finalization and submission outcomes,
+// tracking how much work actually reached KEY mining
+type EpochMetrics struct {
+	EpochsFinalized   int `json:"epochsFinalized"`       // Epochs finalized by the adapter
+	BridgeSucceeded   int `json:"bridgeSucceeded"`       // Epochs accepted by the JavaScript bridge
+	BridgeFailed      int `json:"bridgeFailed"`          // Epochs the bridge failed to accept
+	CallbacksInvoked  int `json:"callbacksInvoked"`      // Epochs delivered to the callback (including fallbacks)
+	CallbackFallbacks int `json:"callbackFallbacks"`     // Callback deliveries made after a bridge failure
+	SkippedLowValue   int `json:"skippedLowValueEpochs"` // Epochs not submitted for having too few successful rounds
+}
+
+// SubnetStats is a point-in-time summary of the adapter's tracking state,
+// intended for dashboards polling a running subnet
+type SubnetStats struct {
+	SubnetID          string         `json:"subnetId"`
+	TotalEvents       int            `json:"totalEvents"`
+	EventsByType      map[string]int `json:"eventsByType"`
+	RequestsProcessed int            `json:"requestsProcessed"`
+	CurrentEpoch      int            `json:"currentEpoch"`
+	RoundsInEpoch     int            `json:"roundsInEpoch"`
+	LatestVLCState    map[int]int    `json:"latestVlcState"`
 }
 
-// NewSubnetGraphAdapter creates a new graph adapter for subnet visualization
-func NewSubnetGraphAdapter(subnetID string, nodeID int, nodeAddr string) *SubnetGraphAdapter {
+// GenesisConfig customizes the genesis event created for a subnet.
+// A zero value reproduces the default genesis message and empty clock.
+// Participants must start from InitialClock (see LatestClock) so their events
+// order after genesis.
+type GenesisConfig struct {
+	Message      string      // Genesis event value (defaults to the standard PoCW message)
+	InitialClock map[int]int // VLC participants seeded at genesis
+}
+
+// NewSubnetGraphAdapter creates a new graph adapter for subnet visualization.
+// An optional GenesisConfig customizes the genesis event.
+func NewSubnetGraphAdapter(subnetID string, nodeID int, nodeAddr string, genesis ...GenesisConfig) *SubnetGraphAdapter {
 	sga := &SubnetGraphAdapter{
 		EventGraph:       dgraph.NewEventGraph(nodeID, nodeAddr),
 		SubnetID:         subnetID,
@@ -92,13 +165,31 @@ func NewSubnetGraphAdapter(subnetID string, nodeID int, nodeAddr string) *Subnet
 		roundsInEpoch:    0,
 		bridgeURL:        "", // No default bridge URL - must be explicitly set
 		currentRounds:    make(map[string]*RoundData),
+		lastVLCState:     make(map[int]int),
+		clock:            RealClock{},
+		epochStore:       NewMemoryEpochStore(),
+		requireUserAccept: true,
 	}
+	sga.lastEpochAt = sga.clock.Now()
 	
 	// Create Genesis State immediately
-	sga.createGenesisState()
+	var genesisConfig GenesisConfig
+	if len(genesis) > 0 {
+		genesisConfig = genesis[0]
+	}
+	sga.createGenesisState(genesisConfig)
 	return sga
 }
 
+// SetClock replaces the wall-clock time source, e.g. with a FakeClock for deterministic
+// epoch timing. The current epoch's time window restarts at the new clock's time.
+func (sga *SubnetGraphAdapter) SetClock(clock Clock) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.clock = clock
+	sga.lastEpochAt = clock.Now()
+}
+
 // SetEpochFinalizedCallback sets the callback function to be triggered when an epoch is finalized
 func (sga *SubnetGraphAdapter) SetEpochFinalizedCallback(callback EpochFinalizedCallback) {
 	sga.mu.Lock()
@@ -106,6 +197,13 @@ func (sga *SubnetGraphAdapter) SetEpochFinalizedCallback(callback EpochFinalized
 	sga.epochCallback = callback
 }
 
+// SetRoundCompletedCallback sets the callback function to be triggered when a round completes
+func (sga *SubnetGraphAdapter) SetRoundCompletedCallback(callback RoundCompletedCallback) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.roundCallback = callback
+}
+
 // SetBridgeURL sets the URL for the JavaScript bridge service
 func (sga *SubnetGraphAdapter) SetBridgeURL(url string) {
 	sga.mu.Lock()
@@ -113,8 +211,30 @@ func (sga *SubnetGraphAdapter) SetBridgeURL(url string) {
 	sga.bridgeURL = url
 }
 
-// sendEpochToBridge sends epoch data to the JavaScript bridge via HTTP POST
-func (sga *SubnetGraphAdapter) sendEpochToBridge(epochData *EpochData) error {
+// SetBridgeSecret sets the shared secret used to sign epoch payloads sent to the bridge.
+// An empty secret disables signing.
+func (sga *SubnetGraphAdapter) SetBridgeSecret(secret string) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.bridgeSecret = secret
+}
+
+// signEpochPayload returns the hex-encoded HMAC-SHA256 of the payload under the shared secret.
+//
+// Bridge Verification:
+//   - Read the raw request body before parsing it as JSON
+//   - Compute hex(HMAC-SHA256(secret, body)) over those exact bytes
+//   - Compare it with the X-Epoch-Signature header in constant time
+//   - Reject the submission if the header is missing or does not match
+func signEpochPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendEpochToBridge sends epoch data to the JavaScript bridge via HTTP POST,
+// signing the payload in the X-Epoch-Signature header when a secret is set
+func (sga *SubnetGraphAdapter) sendEpochToBridge(bridgeURL string, bridgeSecret string, epochData *EpochData) error {
 	// Prepare the payload for the bridge
 	payload := map[string]interface{}{
 		"epochNumber":    epochData.EpochNumber,
@@ -124,7 +244,7 @@ func (sga *SubnetGraphAdapter) sendEpochToBridge(epochData *EpochData) error {
 		"vlcClockState":  epochData.VLCClockState,
 		"epochEventId":   epochData.EpochEventID,
 		"parentRoundEventId": epochData.ParentRoundEventID,
-		"timestamp":      time.Now().Unix(),
+		"timestamp":      epochData.FinalizedAt,
 	}
 	
 	// Debug log the detailed rounds being sent
@@ -147,12 +267,15 @@ func (sga *SubnetGraphAdapter) sendEpochToBridge(epochData *EpochData) error {
 	fmt.Printf("📤 Sending epoch data: %d detailed rounds, %d bytes\n", len(epochData.DetailedRounds), len(jsonPayload))
 
 	// Create HTTP request
-	req, err := http.NewRequest("POST", sga.bridgeURL+"/submit-epoch", bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequest("POST", bridgeURL+"/submit-epoch", bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if bridgeSecret != "" {
+		req.Header.Set("X-Epoch-Signature", signEpochPayload(bridgeSecret, jsonPayload))
+	}
 
 	// Send request with timeout
 	client := &http.Client{
@@ -174,15 +297,22 @@ func (sga *SubnetGraphAdapter) sendEpochToBridge(epochData *EpochData) error {
 }
 
 // createGenesisState creates the initial genesis state for the blockchain
-func (sga *SubnetGraphAdapter) createGenesisState() {
+func (sga *SubnetGraphAdapter) createGenesisState(config GenesisConfig) {
 	eventName := "GenesisState"
 	key := "genesis_0"
 	value := "Blockchain Genesis: PoCW Subnet initialized with VLC consensus"
+	if config.Message != "" {
+		value = config.Message
+	}
 	
-	// Genesis has empty VLC clock (no participants yet)
+	// Genesis has empty VLC clock unless initial participants are seeded
 	clockMap := make(map[int]int)
+	for k, v := range config.InitialClock {
+		clockMap[k] = v
+	}
+	sga.lastVLCState = clockMap
 	
-	genesisEventID := sga.EventGraph.AddEvent(
+	genesisEventID := sga.addEvent(
 		eventName,
 		key, 
 		value,
@@ -196,6 +326,7 @@ func (sga *SubnetGraphAdapter) createGenesisState() {
 
 // TrackUserInput records user input that starts a round (validator VLC increment)
 func (sga *SubnetGraphAdapter) TrackUserInput(requestID string, input string, validatorClock *vlc.Clock, parentEventID string) string {
+	sga.makeRoom(1)
 	sga.mu.Lock()
 	defer sga.mu.Unlock()
 
@@ -203,18 +334,14 @@ func (sga *SubnetGraphAdapter) TrackUserInput(requestID string, input string, va
 	sga.roundCounters[requestID]++
 	roundNum := sga.roundCounters[requestID]
 
-	// Initialize or update round data
+	// Initialize or update round data. The round number within the epoch is
+	// assigned when the round completes, so rounds running concurrently are
+	// numbered in the order they finish.
 	if sga.currentRounds[requestID] == nil {
-		// Calculate the round number within the current epoch (1, 2, or 3)
-		// roundsInEpoch starts at 0, so we add 1 to get the current round number
-		epochRoundNumber := sga.roundsInEpoch + 1
 		sga.currentRounds[requestID] = &RoundData{
-			RoundNumber:   epochRoundNumber,
 			RequestID:     requestID,
 			VLCClockState: make(map[int]int),
 		}
-		// Debug: Log round creation
-		// fmt.Printf("🔍 Created round %d data for request %s\n", epochRoundNumber, requestID)
 	}
 	sga.currentRounds[requestID].UserInput = input
 	sga.currentRounds[requestID].VLCClockState = vlcToMap(validatorClock)
@@ -238,29 +365,39 @@ func (sga *SubnetGraphAdapter) TrackUserInput(requestID string, input string, va
 		parents = append(parents, parentEventID)
 	}
 
-	eventID := sga.EventGraph.AddEvent(
+	eventID := sga.addEvent(
 		eventName,
 		key,
 		value,
 		clockMap,
 		parents,
 	)
+	if eventID != "" {
+		sga.lastVLCState = clockMap
+	}
 
 	return eventID
 }
 
 // TrackMinerResponse records miner processing with semantic naming (miner VLC increment)
 func (sga *SubnetGraphAdapter) TrackMinerResponse(requestID string, response *MinerResponseMessage, parentEventID string) string {
+	sga.makeRoom(1)
 	sga.mu.Lock()
 	defer sga.mu.Unlock()
 
-	// Update round data with miner response
+	// Update round data with miner response. Info-request rounds keep the first
+	// question and its clock separately from the final output that is validated.
 	if round := sga.currentRounds[requestID]; round != nil {
 		if response.OutputType == OutputReady {
 			round.MinerOutput = response.Output
 			round.MinerOutputType = "output_ready"
+			round.MinerOutputVLC = vlcToMap(response.VLCClock)
 		} else {
-			round.InfoRequest = response.InfoRequest
+			if round.InfoRequestCount == 0 {
+				round.InfoRequest = response.InfoRequest
+				round.InfoRequestVLC = vlcToMap(response.VLCClock)
+			}
+			round.InfoRequestCount++
 			round.MinerOutputType = "info_request"
 		}
 		// Update VLC state
@@ -289,25 +426,31 @@ func (sga *SubnetGraphAdapter) TrackMinerResponse(requestID string, response *Mi
 		parents = append(parents, parentEventID)
 	}
 
-	eventID := sga.EventGraph.AddEvent(
+	eventID := sga.addEvent(
 		eventName,
 		key,
 		value,
 		clockMap,
 		parents,
 	)
+	if eventID != "" {
+		sga.lastVLCState = clockMap
+	}
 
 	return eventID
 }
 
 // TrackInfoResponse records user providing additional context (validator VLC increment)
 func (sga *SubnetGraphAdapter) TrackInfoResponse(requestID string, additionalInfo string, validatorClock *vlc.Clock, parentEventID string) string {
+	sga.makeRoom(1)
 	sga.mu.Lock()
 	defer sga.mu.Unlock()
 
-	// Update round data with info response
+	// Update round data with the answer to the first info request
 	if round := sga.currentRounds[requestID]; round != nil {
-		round.InfoResponse = additionalInfo
+		if round.InfoResponse == "" {
+			round.InfoResponse = additionalInfo
+		}
 		// Update VLC state
 		for k, v := range vlcToMap(validatorClock) {
 			round.VLCClockState[k] = v
@@ -325,38 +468,77 @@ func (sga *SubnetGraphAdapter) TrackInfoResponse(requestID string, additionalInf
 		parents = append(parents, parentEventID)
 	}
 
-	eventID := sga.EventGraph.AddEvent(
+	eventID := sga.addEvent(
 		eventName,
 		key,
 		value,
 		clockMap,
 		parents,
 	)
+	if eventID != "" {
+		sga.lastVLCState = clockMap
+	}
 
 	return eventID
 }
 
-// TrackRoundComplete records round completion with comprehensive workflow result (validator VLC increment)
-func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int, validatorClock *vlc.Clock, consensusResult string, userFeedback string, userAccept bool, finalResult string, parentEventID string) string {
+// TrackRoundComplete records round completion with comprehensive workflow result (validator VLC increment).
+// consensusAccepted reports whether validators accepted the output; whether the round
+// counts as successful for rewards is decided by SetRewardRequiresUserAcceptance.
+func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int, validatorClock *vlc.Clock, consensusResult string, consensusAccepted bool, userFeedback string, userAccept bool, finalResult string, parentEventID string) string {
+	sga.makeRoom(2) // The round event and its NextRound or EpochFinalized successor
+	sga.mu.Lock()
+	nextEventID, roundEventID, success := sga.trackRoundCompleteLocked(requestID, roundNum, validatorClock, consensusResult, consensusAccepted, userFeedback, userAccept, finalResult, parentEventID)
+	sga.persistStateLocked()
+	roundCallback := sga.roundCallback
+	sga.mu.Unlock()
+
+	// Invoke outside the lock so the callback may query the adapter
+	if roundCallback != nil && roundEventID != "" {
+		roundCallback(RoundResult{
+			SubnetID:          sga.SubnetID,
+			RequestID:         requestID,
+			RoundNumber:       roundNum,
+			EventID:           roundEventID,
+			ConsensusResult:   consensusResult,
+			ConsensusAccepted: consensusAccepted,
+			UserFeedback:      userFeedback,
+			UserAccept:        userAccept,
+			FinalResult:       finalResult,
+			Success:           success,
+			VLCClockState:     vlcToMap(validatorClock),
+		})
+	}
+	return nextEventID
+}
+
+// SetRewardRequiresUserAcceptance sets whether a round only counts as successful, and
+// so eligible for KEY mining rewards, when the user also accepted the output (the default).
+// When false, validator consensus acceptance alone makes a round successful.
+func (sga *SubnetGraphAdapter) SetRewardRequiresUserAcceptance(required bool) {
 	sga.mu.Lock()
 	defer sga.mu.Unlock()
+	sga.requireUserAccept = required
+}
 
-	// Complete round data with final results
-	if round := sga.currentRounds[requestID]; round != nil {
-		round.ConsensusResult = consensusResult
-		round.UserFeedback = userFeedback
-		round.UserAccept = userAccept
-		round.FinalResult = finalResult
-		round.Success = userAccept && finalResult == "OUTPUT DELIVERED TO USER"
-		// Final VLC state update
-		for k, v := range vlcToMap(validatorClock) {
-			round.VLCClockState[k] = v
-		}
+// roundSucceeded decides RoundData.Success, the single success flag used for rewards,
+// epoch task counts and round event coloring. The caller must hold sga.mu.
+func (sga *SubnetGraphAdapter) roundSucceeded(consensusAccepted bool, userAccept bool, finalResult string) bool {
+	if sga.requireUserAccept {
+		return consensusAccepted && userAccept && finalResult == "OUTPUT DELIVERED TO USER"
 	}
+	return consensusAccepted
+}
+
+// trackRoundCompleteLocked records the round completion; the caller must hold sga.mu.
+// Returns the event the next round chains from, the round's own completion event
+// and whether the round succeeded.
+func (sga *SubnetGraphAdapter) trackRoundCompleteLocked(requestID string, roundNum int, validatorClock *vlc.Clock, consensusResult string, consensusAccepted bool, userFeedback string, userAccept bool, finalResult string, parentEventID string) (string, string, bool) {
+	success := sga.roundSucceeded(consensusAccepted, userAccept, finalResult)
 
 	// Determine semantic event name based on final outcome
 	var eventName string
-	if userAccept && finalResult == "OUTPUT DELIVERED TO USER" {
+	if success {
 		eventName = "RoundSuccess" // Will be colored green
 	} else {
 		eventName = "RoundFailed" // Will be colored red
@@ -375,32 +557,111 @@ func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int
 		parents = append(parents, parentEventID)
 	}
 
-	eventID := sga.EventGraph.AddEvent(
+	eventID := sga.addEvent(
 		eventName,
 		key,
 		value,
 		clockMap,
 		parents,
 	)
+	if eventID == "" {
+		// The round was not recorded, so it does not count toward the epoch
+		return "", "", success
+	}
+	sga.lastVLCState = clockMap
+
+	// Complete round data with final results
+	if round := sga.currentRounds[requestID]; round != nil {
+		round.RoundNumber = sga.roundsInEpoch + 1 // Position of this round within the epoch
+		sga.epochRequests = append(sga.epochRequests, requestID)
+		round.ConsensusResult = consensusResult
+		round.ConsensusAccepted = consensusAccepted
+		round.UserFeedback = userFeedback
+		round.UserAccept = userAccept
+		round.FinalResult = finalResult
+		round.Success = success
+		// Final VLC state update
+		for k, v := range vlcToMap(validatorClock) {
+			round.VLCClockState[k] = v
+		}
+	}
 
 	// Track completed round and update chain
 	sga.completedRounds = append(sga.completedRounds, eventID)
 	sga.lastEventInChain = eventID
 	sga.roundsInEpoch++
 	
-	// Determine what comes next in the blockchain structure
+	// Determine what comes next in the blockchain structure. If the connector
+	// event is refused, the next round chains from this round's event instead.
 	if sga.roundsInEpoch == 3 {
 		// End of epoch - create EpochFinalized
-		epochEventID := sga.createEpochFinalization(validatorClock, eventID)
-		sga.lastEventInChain = epochEventID
+		if epochEventID := sga.createEpochFinalization(validatorClock, eventID); epochEventID != "" {
+			sga.lastEventInChain = epochEventID
+		}
 		sga.roundsInEpoch = 0 // Reset for next epoch
-		return epochEventID
 	} else {
 		// Middle of epoch - create NextRound connector
-		nextRoundEventID := sga.createNextRoundConnector(validatorClock, eventID)
-		sga.lastEventInChain = nextRoundEventID
-		return nextRoundEventID
+		if nextRoundEventID := sga.createNextRoundConnector(validatorClock, eventID); nextRoundEventID != "" {
+			sga.lastEventInChain = nextRoundEventID
+		}
 	}
+	return sga.lastEventInChain, eventID, success
+}
+
+// StartEpochTimer finalizes partial epochs on a timer so that subnets with sparse
+// traffic still submit their work. If at least one round has completed and
+// maxEpochDuration has elapsed since the last finalization, the partial epoch is
+// finalized. Close the returned channel to stop the timer. A non-positive
+// maxEpochDuration disables the timer; the returned channel may still be closed.
+func (sga *SubnetGraphAdapter) StartEpochTimer(maxEpochDuration time.Duration) chan struct{} {
+	done := make(chan struct{})
+	if maxEpochDuration <= 0 {
+		return done
+	}
+
+	checkInterval := maxEpochDuration / 4
+	if checkInterval <= 0 {
+		checkInterval = maxEpochDuration
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				sga.finalizeExpiredEpoch(maxEpochDuration)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return done
+}
+
+// finalizeExpiredEpoch forces finalization of the current partial epoch if it has
+// outlived maxEpochDuration. It holds the adapter lock, so it cannot race with
+// round-driven finalization in TrackRoundComplete.
+func (sga *SubnetGraphAdapter) finalizeExpiredEpoch(maxEpochDuration time.Duration) {
+	sga.makeRoom(1)
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	if sga.roundsInEpoch == 0 || len(sga.completedRounds) == 0 || sga.clock.Now().Sub(sga.lastEpochAt) < maxEpochDuration {
+		return
+	}
+
+	fmt.Printf("⏰ Epoch %d exceeded %v with %d round(s) - finalizing partial epoch\n",
+		sga.epochCount+1, maxEpochDuration, sga.roundsInEpoch)
+
+	// Chain from the last round's NextRound connector, so it does not dangle as a branch
+	if epochEventID := sga.createEpochFinalization(mapToVLC(sga.lastVLCState), sga.lastEventInChain); epochEventID != "" {
+		sga.lastEventInChain = epochEventID
+	}
+	sga.roundsInEpoch = 0
+	sga.persistStateLocked()
 }
 
 // createNextRoundConnector creates transition nodes between rounds within an epoch
@@ -411,7 +672,7 @@ func (sga *SubnetGraphAdapter) createNextRoundConnector(validatorClock *vlc.Cloc
 	
 	clockMap := vlcToMap(validatorClock)
 	
-	nextRoundEventID := sga.EventGraph.AddEvent(
+	nextRoundEventID := sga.addEvent(
 		eventName,
 		key,
 		value,
@@ -422,109 +683,226 @@ func (sga *SubnetGraphAdapter) createNextRoundConnector(validatorClock *vlc.Cloc
 	return nextRoundEventID
 }
 
-// createEpochFinalization creates epoch milestone events that chain rounds together blockchain-style
-func (sga *SubnetGraphAdapter) createEpochFinalization(validatorClock *vlc.Clock, parentRoundEventID string) string {
+// createEpochFinalization creates epoch milestone events that chain rounds together blockchain-style.
+// parentEventID is the tip of the chain: the last round's event, or its NextRound
+// connector when a partial epoch is finalized.
+func (sga *SubnetGraphAdapter) createEpochFinalization(validatorClock *vlc.Clock, parentEventID string) string {
 	sga.epochCount++
+	sga.lastEpochAt = sga.clock.Now()
+	sga.recordEpochMetric(func(m *EpochMetrics) { m.EpochsFinalized++ })
 	
 	eventName := "EpochFinalized"
 	key := fmt.Sprintf("epoch_%d_finalized", sga.epochCount)
 	
-	value := fmt.Sprintf("Epoch %d: Finalized with 3 rounds | VLC State: Miner=%d, Validator=%d", 
-		sga.epochCount, 
-		validatorClock.Values[1], 
-		validatorClock.Values[2])
+	value := fmt.Sprintf("Epoch %d: Finalized with %d rounds | VLC State: %s",
+		sga.epochCount,
+		len(sga.completedRounds),
+		describeClock(sga.SubnetID, validatorClock))
 	
 	clockMap := vlcToMap(validatorClock)
 	
 	// EpochFinalized connects to the last round of the epoch
-	epochEventID := sga.EventGraph.AddEvent(
+	epochEventID := sga.addEvent(
 		eventName,
 		key,
 		value,
 		clockMap,
-		[]string{parentRoundEventID}, // Connect to round 3 of the epoch
+		[]string{parentEventID}, // Connect to the end of the epoch's chain
 	)
 	
-	// Trigger epoch finalized callback or HTTP bridge if configured
-	if sga.epochCallback != nil || sga.bridgeURL != "" {
-		epochData := &EpochData{
-			EpochNumber:        sga.epochCount,
-			SubnetID:           sga.SubnetID,
-			CompletedRounds:    make([]string, len(sga.completedRounds)),
-			DetailedRounds:     make([]RoundData, 0),
-			VLCClockState:      make(map[int]int),
-			EpochEventID:       epochEventID,
-			ParentRoundEventID: parentRoundEventID,
-		}
-		
-		// Copy completed rounds for this epoch (last 3 rounds)
-		copy(epochData.CompletedRounds, sga.completedRounds)
-		
-		// IMPORTANT: Copy detailed round data BEFORE clearing currentRounds
-		fmt.Printf("🔍 DEBUG - Current rounds in memory: %d\n", len(sga.currentRounds))
-		for requestID, roundData := range sga.currentRounds {
-			if roundData != nil {
-				// Create a copy of the round data
-				epochData.DetailedRounds = append(epochData.DetailedRounds, *roundData)
-				inputPreview := roundData.UserInput
-				if len(inputPreview) > 50 {
-					inputPreview = inputPreview[:50] + "..."
-				}
-				fmt.Printf("📋 Including round %d data for request %s: %s (Success: %t)\n", roundData.RoundNumber, requestID, inputPreview, roundData.Success)
+	// Collect the epoch's data for the epoch store and for the callback or HTTP bridge
+	epochNumber := sga.epochCount
+	bridgeURL := sga.bridgeURL
+	bridgeSecret := sga.bridgeSecret
+	epochCallback := sga.epochCallback
+	epochData := &EpochData{
+		EpochNumber:        epochNumber,
+		SubnetID:           sga.SubnetID,
+		CompletedRounds:    make([]string, len(sga.completedRounds)),
+		DetailedRounds:     make([]RoundData, 0),
+		VLCClockState:      make(map[int]int),
+		EpochEventID:       epochEventID,
+		ParentRoundEventID: sga.completedRounds[len(sga.completedRounds)-1], // Last round of the epoch
+		FinalizedAt:        sga.lastEpochAt.Unix(),
+	}
+	
+	// Copy completed rounds for this epoch (last 3 rounds)
+	copy(epochData.CompletedRounds, sga.completedRounds)
+	
+	// IMPORTANT: Copy detailed round data BEFORE clearing the epoch's rounds
+	fmt.Printf("🔍 DEBUG - Completed rounds in epoch: %d\n", len(sga.epochRequests))
+	for _, requestID := range sga.epochRequests {
+		if roundData := sga.currentRounds[requestID]; roundData != nil {
+			// Create a copy of the round data
+			epochData.DetailedRounds = append(epochData.DetailedRounds, *roundData)
+			inputPreview := roundData.UserInput
+			if len(inputPreview) > 50 {
+				inputPreview = inputPreview[:50] + "..."
 			}
+			fmt.Printf("📋 Including round %d data for request %s: %s (Success: %t)\n", roundData.RoundNumber, requestID, inputPreview, roundData.Success)
 		}
-		fmt.Printf("🔍 DEBUG - Copied %d detailed rounds to epochData\n", len(epochData.DetailedRounds))
-		
-		// Copy VLC clock state
-		for nodeID, value := range validatorClock.Values {
-			epochData.VLCClockState[int(nodeID)] = int(value)
+	}
+	fmt.Printf("🔍 DEBUG - Copied %d detailed rounds to epochData\n", len(epochData.DetailedRounds))
+	
+	// Copy VLC clock state
+	for nodeID, value := range validatorClock.Values {
+		epochData.VLCClockState[int(nodeID)] = int(value)
+	}
+	
+	// Save the epoch so its details stay queryable after submission
+	if sga.epochStore != nil {
+		if err := sga.epochStore.Save(epochData); err != nil {
+			fmt.Printf("⚠️  Failed to save epoch %d details: %v\n", epochNumber, err)
 		}
-		
-		fmt.Printf("🚀 Epoch %d finalized - triggering mainnet submission\n", sga.epochCount)
+	}
+	
+	// Only epochs with enough successful rounds are worth submitting for KEY mining
+	successfulRounds := 0
+	for _, round := range epochData.DetailedRounds {
+		if round.Success {
+			successfulRounds++
+		}
+	}
+	submit := epochCallback != nil || bridgeURL != ""
+	if submit && successfulRounds < sga.minMiningRounds {
+		fmt.Printf("⏭️  Epoch %d skipped for mainnet submission: %d successful rounds, %d required\n",
+			epochNumber, successfulRounds, sga.minMiningRounds)
+		sga.recordEpochMetric(func(m *EpochMetrics) { m.SkippedLowValue++ })
+		submit = false
+	}
+	
+	// Trigger epoch finalized callback or HTTP bridge if configured
+	if submit {
+		fmt.Printf("🚀 Epoch %d finalized - triggering mainnet submission\n", epochNumber)
 		
 		// Send epoch data to JavaScript bridge asynchronously
 		go func() {
 			// Try HTTP bridge first if URL is set
-			if sga.bridgeURL != "" {
+			if bridgeURL != "" {
 				fmt.Printf("📡 Sending Epoch %d data to JavaScript bridge...\n", epochData.EpochNumber)
-				if err := sga.sendEpochToBridge(epochData); err != nil {
+				if err := sga.sendEpochToBridge(bridgeURL, bridgeSecret, epochData); err != nil {
 					fmt.Printf("❌ Failed to send epoch data to bridge: %v\n", err)
-					if sga.epochCallback != nil {
+					sga.recordEpochMetric(func(m *EpochMetrics) { m.BridgeFailed++ })
+					if epochCallback != nil {
 						fmt.Printf("🔄 Falling back to callback method...\n")
-						sga.epochCallback(sga.epochCount, sga.SubnetID, epochData)
+						sga.recordEpochMetric(func(m *EpochMetrics) { m.CallbacksInvoked++; m.CallbackFallbacks++ })
+						epochCallback(epochNumber, sga.SubnetID, epochData)
 					}
 				} else {
 					fmt.Printf("✅ Epoch %d submitted to mainnet via bridge!\n", epochData.EpochNumber)
+					sga.recordEpochMetric(func(m *EpochMetrics) { m.BridgeSucceeded++ })
 				}
-			} else if sga.epochCallback != nil {
+			} else if epochCallback != nil {
 				// Use callback method if no bridge URL
-				sga.epochCallback(sga.epochCount, sga.SubnetID, epochData)
+				sga.recordEpochMetric(func(m *EpochMetrics) { m.CallbacksInvoked++ })
+				epochCallback(epochNumber, sga.SubnetID, epochData)
 			}
 		}()
 	}
 	
-	// Reset completed rounds and current round data for next epoch
+	// Reset completed rounds for next epoch. Rounds still in flight stay tracked
+	// so they can complete into the next epoch.
 	sga.completedRounds = make([]string, 0)
-	sga.currentRounds = make(map[string]*RoundData)
+	for _, requestID := range sga.epochRequests {
+		delete(sga.currentRounds, requestID)
+	}
+	sga.epochRequests = nil
 	
 	return epochEventID
 }
 
-// CommitGraph commits all tracked events to Dgraph for visualization
-func (sga *SubnetGraphAdapter) CommitGraph() error {
+// SetMinSuccessfulRoundsForMining sets how many successful rounds a finalized epoch
+// needs before it is submitted to the bridge or callback for KEY mining. Epochs below
+// the threshold are still finalized and stored, but not submitted. Zero submits every epoch.
+func (sga *SubnetGraphAdapter) SetMinSuccessfulRoundsForMining(rounds int) {
 	sga.mu.Lock()
-	eventCount := len(sga.EventGraph.Events) // Get count before committing (as commit clears events)
-	sga.mu.Unlock()
-	
-	// Handle case where Dgraph is not available
+	defer sga.mu.Unlock()
+	sga.minMiningRounds = rounds
+}
+
+// SetCommitBatchSize limits how many events each Dgraph mutation commits (0 = all at once)
+func (sga *SubnetGraphAdapter) SetCommitBatchSize(size int) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	sga.EventGraph.EventMu.Lock()
+	sga.EventGraph.CommitBatchSize = size
+	sga.EventGraph.EventMu.Unlock()
+}
+
+// SetMaxPendingEvents caps the number of uncommitted events held in memory.
+// When the cap is reached the adapter auto-commits to Dgraph; if that fails,
+// new events are refused until a commit succeeds. Zero disables the cap.
+// Track methods return "" for a refused event and leave the event chain unchanged;
+// a refused round completion is not counted toward the epoch or reported to the
+// round callback.
+func (sga *SubnetGraphAdapter) SetMaxPendingEvents(max int) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	sga.EventGraph.EventMu.Lock()
+	sga.EventGraph.MaxPendingEvents = max
+	sga.EventGraph.EventMu.Unlock()
+}
+
+// GetPendingEventCount returns the number of events awaiting commit to Dgraph
+func (sga *SubnetGraphAdapter) GetPendingEventCount() int {
+	return sga.EventGraph.PendingCount()
+}
+
+// makeRoom auto-commits pending events to Dgraph if adding count more would exceed
+// the pending event limit. It must be called without holding sga.mu, so other
+// trackers are not blocked while the commit retries.
+//
+// The room is not reserved: a concurrent tracker may fill it between makeRoom and
+// the add. TryAddEvent re-checks the limit under EventMu, so the cap still holds,
+// and the losing event is refused as if the auto-commit had failed.
+func (sga *SubnetGraphAdapter) makeRoom(count int) {
+	sga.EventGraph.EventMu.RLock()
+	max := sga.EventGraph.MaxPendingEvents
+	sga.EventGraph.EventMu.RUnlock()
+
+	if max <= 0 || sga.EventGraph.PendingCount()+count <= max {
+		return
+	}
+	fmt.Printf("📦 %d pending events reached limit - auto-committing to Dgraph\n", max)
+	if err := sga.commitPending(); err != nil {
+		fmt.Printf("❌ Auto-commit failed: %v\n", err)
+	}
+}
+
+// addEvent adds an event to the graph. Returns "" if the pending event limit was
+// reached; callers then leave their chain state unchanged.
+func (sga *SubnetGraphAdapter) addEvent(name string, key string, value string, clock map[int]int, parentIDs []string) string {
+	eventID, err := sga.EventGraph.TryAddEvent(name, key, value, clock, parentIDs)
+	if err != nil {
+		fmt.Printf("❌ Refusing %s event %s: %v\n", name, key, err)
+		return ""
+	}
+	return eventID
+}
+
+// commitPending commits pending events, converting a Dgraph client panic into an error
+func (sga *SubnetGraphAdapter) commitPending() (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Dgraph commit failed: %v (continuing without graph visualization)\n", r)
+			err = fmt.Errorf("dgraph commit panicked: %v", r)
 		}
 	}()
+	return sga.EventGraph.CommitToGraph()
+}
+
+// CommitGraph commits all tracked events to Dgraph for visualization
+func (sga *SubnetGraphAdapter) CommitGraph() error {
+	sga.mu.Lock()
+	eventCount := sga.EventGraph.PendingCount() // Get count before committing (as commit clears events)
+	sga.mu.Unlock()
 	
-	err := sga.EventGraph.CommitToGraph()
-	if err == nil {
+	// Handle case where Dgraph is not available
+	err := sga.commitPending()
+	if err != nil {
+		fmt.Printf("Dgraph commit failed: %v (continuing without graph visualization)\n", err)
+	} else {
 		fmt.Printf("Committed %d events to Dgraph successfully!\n", eventCount)
 	}
 	return err
@@ -538,6 +916,60 @@ func (sga *SubnetGraphAdapter) GetEventCount() int {
 	return len(sga.EventGraph.Events)
 }
 
+// EventSnapshot is the serialized form of an adapter's pending event chain
+type EventSnapshot struct {
+	SubnetID string         `json:"subnetId"`
+	Events   []models.Event `json:"events"`
+}
+
+// TopologicalOrder returns the pending (uncommitted) events sorted by causality,
+// parents and VLC-earlier events first, for visualization tools other than Dgraph
+func (sga *SubnetGraphAdapter) TopologicalOrder() ([]models.Event, error) {
+	sga.mu.RLock()
+	defer sga.mu.RUnlock()
+	return sga.EventGraph.TopologicalOrder()
+}
+
+// ExportEvents serializes the pending (uncommitted) events, including their
+// names, VLC clocks and parent links, so a run can be replayed later
+func (sga *SubnetGraphAdapter) ExportEvents() ([]byte, error) {
+	sga.mu.RLock()
+	defer sga.mu.RUnlock()
+
+	snapshot := EventSnapshot{
+		SubnetID: sga.SubnetID,
+		Events:   sga.EventGraph.PendingEvents(),
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event snapshot: %v", err)
+	}
+	return data, nil
+}
+
+// ImportEvents rebuilds the pending event chain from data produced by ExportEvents.
+// Any events currently pending in the adapter are replaced, and new events can only
+// link to imported ones. The snapshot must belong to the same subnet.
+func (sga *SubnetGraphAdapter) ImportEvents(data []byte) error {
+	var snapshot EventSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal event snapshot: %v", err)
+	}
+	if snapshot.SubnetID != sga.SubnetID {
+		return fmt.Errorf("event snapshot belongs to subnet %q, not %q", snapshot.SubnetID, sga.SubnetID)
+	}
+
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	sga.EventGraph.LoadEvents(snapshot.Events)
+	if len(snapshot.Events) > 0 {
+		sga.lastEventInChain = snapshot.Events[len(snapshot.Events)-1].ID
+	}
+	return nil
+}
+
 // vlcToMap converts VLC clock to map format for JSON serialization
 func vlcToMap(clock *vlc.Clock) map[int]int {
 	if clock == nil {
@@ -552,6 +984,64 @@ func vlcToMap(clock *vlc.Clock) map[int]int {
 	return result
 }
 
+// GetSubnetStats returns a summary of tracked events, epochs and the latest VLC state.
+// CurrentEpoch is the epoch in progress (finalized epochs + 1).
+func (sga *SubnetGraphAdapter) GetSubnetStats() SubnetStats {
+	sga.mu.RLock()
+	defer sga.mu.RUnlock()
+
+	events := sga.EventGraph.PendingEvents()
+	stats := SubnetStats{
+		SubnetID:          sga.SubnetID,
+		TotalEvents:       len(events),
+		EventsByType:      make(map[string]int),
+		RequestsProcessed: len(sga.roundCounters),
+		CurrentEpoch:      sga.epochCount + 1,
+		RoundsInEpoch:     sga.roundsInEpoch,
+		LatestVLCState:    make(map[int]int),
+	}
+	for _, event := range events {
+		stats.EventsByType[event.Name]++
+	}
+	for k, v := range sga.lastVLCState {
+		stats.LatestVLCState[k] = v
+	}
+	return stats
+}
+
+// LatestClock returns the VLC clock of the most recently tracked event, or the genesis
+// clock if no round has been tracked yet. Participants seed their clocks from it so
+// their next events order after everything already in the graph.
+func (sga *SubnetGraphAdapter) LatestClock() *vlc.Clock {
+	sga.mu.RLock()
+	defer sga.mu.RUnlock()
+	return mapToVLC(sga.lastVLCState)
+}
+
+// GetEpochMetrics returns a snapshot of the epoch submission counters.
+// Bridge and callback counters update asynchronously after finalization.
+func (sga *SubnetGraphAdapter) GetEpochMetrics() EpochMetrics {
+	sga.metricsMu.Lock()
+	defer sga.metricsMu.Unlock()
+	return sga.epochMetrics
+}
+
+// recordEpochMetric applies an update to the epoch submission counters
+func (sga *SubnetGraphAdapter) recordEpochMetric(update func(*EpochMetrics)) {
+	sga.metricsMu.Lock()
+	defer sga.metricsMu.Unlock()
+	update(&sga.epochMetrics)
+}
+
+// mapToVLC converts a map-format clock back into a VLC clock
+func mapToVLC(clockMap map[int]int) *vlc.Clock {
+	clock := vlc.New()
+	for k, v := range clockMap {
+		clock.Values[uint64(k)] = uint64(v)
+	}
+	return clock
+}
+
 // PrintGraphSummary prints a summary of tracked events
 func (sga *SubnetGraphAdapter) PrintGraphSummary() {
 	sga.mu.RLock()
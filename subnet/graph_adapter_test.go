@@ -0,0 +1,771 @@
+package subnet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph"
+	"github.com/hetu-project/Intelligence-KEY-Mining/dgraph/dgraphtest"
+	"github.com/hetu-project/Intelligence-KEY-Mining/models"
+	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
+)
+
+// trackRound records one complete round the way the demo coordinator does, advancing
+// the shared clock for each step, and returns the event the next round chains from
+func trackRound(t *testing.T, sga *SubnetGraphAdapter, clock *vlc.Clock, requestID string, roundNum int, accept bool) string {
+	t.Helper()
+
+	clock.Inc(2)
+	inputEventID := sga.TrackUserInput(requestID, "input for "+requestID, clock, "")
+	clock.Inc(1)
+	minerEventID := sga.TrackMinerResponse(requestID, &MinerResponseMessage{
+		OutputType:  OutputReady,
+		Output:      "output for " + requestID,
+		VLCClock:    clock.Copy(),
+		InputNumber: roundNum,
+	}, inputEventID)
+
+	clock.Inc(2)
+	consensusResult, finalResult := "ACCEPTED", "OUTPUT DELIVERED TO USER"
+	if !accept {
+		consensusResult, finalResult = "REJECTED", "OUTPUT REJECTED"
+	}
+	return sga.TrackRoundComplete(requestID, roundNum, clock, consensusResult, accept, "feedback", accept, finalResult, minerEventID)
+}
+
+// trackRounds records count accepted rounds with request IDs req-1, req-2, ...
+func trackRounds(t *testing.T, sga *SubnetGraphAdapter, clock *vlc.Clock, count int) {
+	t.Helper()
+	for i := 1; i <= count; i++ {
+		trackRound(t, sga, clock, fmt.Sprintf("req-%d", i), i, true)
+	}
+}
+
+// parentIDs maps each event ID in the graph to the IDs of its pending parents
+func parentIDs(eg *dgraph.EventGraph) map[string][]string {
+	events := eg.PendingEvents()
+	idByUID := make(map[string]string, len(events))
+	for _, event := range events {
+		idByUID[event.UID] = event.ID
+	}
+
+	parents := make(map[string][]string, len(events))
+	for _, event := range events {
+		parents[event.ID] = []string{}
+		for _, parent := range event.Parent {
+			parents[event.ID] = append(parents[event.ID], idByUID[parent.UID])
+		}
+	}
+	return parents
+}
+
+func TestExportImportMultiEpochRoundTrip(t *testing.T) {
+	source := NewSubnetGraphAdapter("subnet-test", 1, "node-1")
+	trackRounds(t, source, vlc.New(), 7) // Two full epochs and one round of the third
+	if got := source.GetSubnetStats().CurrentEpoch; got != 3 {
+		t.Fatalf("CurrentEpoch = %d, want 3", got)
+	}
+
+	data, err := source.ExportEvents()
+	if err != nil {
+		t.Fatalf("ExportEvents() error: %v", err)
+	}
+
+	// A different node ID gives the target's own genesis event an ID the snapshot lacks
+	target := NewSubnetGraphAdapter("subnet-test", 2, "node-2")
+	if err := target.ImportEvents(data); err != nil {
+		t.Fatalf("ImportEvents() error: %v", err)
+	}
+
+	if got, want := target.GetEventCount(), source.GetEventCount(); got != want {
+		t.Fatalf("imported %d events, want %d", got, want)
+	}
+	want := parentIDs(source.EventGraph)
+	got := parentIDs(target.EventGraph)
+	for id, wantParents := range want {
+		if fmt.Sprint(got[id]) != fmt.Sprint(wantParents) {
+			t.Errorf("event %s parents = %v, want %v", id, got[id], wantParents)
+		}
+	}
+	if _, ok := target.EventGraph.UIDMap["e2_1"]; ok {
+		t.Errorf("target's replaced genesis event is still linkable after import")
+	}
+
+	// The next round chains from the last imported event
+	events := source.EventGraph.PendingEvents()
+	lastID := events[len(events)-1].ID
+	clock := vlc.New()
+	clock.Values[1], clock.Values[2] = 100, 100
+	clock.Inc(2)
+	inputID := target.TrackUserInput("req-next", "next input", clock, "")
+	if parents := parentIDs(target.EventGraph)[inputID]; len(parents) != 1 || parents[0] != lastID {
+		t.Errorf("next UserInput parents = %v, want [%s]", parents, lastID)
+	}
+}
+
+func TestImportEventsRejectsOtherSubnet(t *testing.T) {
+	source := NewSubnetGraphAdapter("subnet-a", 1, "node-1")
+	trackRounds(t, source, vlc.New(), 1)
+	data, err := source.ExportEvents()
+	if err != nil {
+		t.Fatalf("ExportEvents() error: %v", err)
+	}
+
+	target := NewSubnetGraphAdapter("subnet-b", 1, "node-1")
+	before := target.GetEventCount()
+	if err := target.ImportEvents(data); err == nil {
+		t.Fatal("ImportEvents() accepted a snapshot from another subnet")
+	}
+	if got := target.GetEventCount(); got != before {
+		t.Errorf("rejected import changed pending events from %d to %d", before, got)
+	}
+}
+
+func TestGetSubnetStatsAfterRounds(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-stats", 1, "node-1")
+	trackRounds(t, sga, vlc.New(), 4) // One full epoch plus one round
+
+	stats := sga.GetSubnetStats()
+	if stats.SubnetID != "subnet-stats" {
+		t.Errorf("SubnetID = %q, want subnet-stats", stats.SubnetID)
+	}
+	if stats.RequestsProcessed != 4 {
+		t.Errorf("RequestsProcessed = %d, want 4", stats.RequestsProcessed)
+	}
+	if stats.CurrentEpoch != 2 || stats.RoundsInEpoch != 1 {
+		t.Errorf("CurrentEpoch/RoundsInEpoch = %d/%d, want 2/1", stats.CurrentEpoch, stats.RoundsInEpoch)
+	}
+
+	wantByType := map[string]int{
+		"GenesisState":   1,
+		"UserInput":      4,
+		"MinerOutput":    4,
+		"RoundSuccess":   4,
+		"NextRound":      3,
+		"EpochFinalized": 1,
+	}
+	total := 0
+	for name, want := range wantByType {
+		total += want
+		if got := stats.EventsByType[name]; got != want {
+			t.Errorf("EventsByType[%s] = %d, want %d", name, got, want)
+		}
+	}
+	if stats.TotalEvents != total {
+		t.Errorf("TotalEvents = %d, want %d", stats.TotalEvents, total)
+	}
+
+	wantVLC := map[int]int{1: 4, 2: 8}
+	if fmt.Sprint(stats.LatestVLCState) != fmt.Sprint(wantVLC) {
+		t.Errorf("LatestVLCState = %v, want %v", stats.LatestVLCState, wantVLC)
+	}
+}
+
+func TestGenesisConfigMessage(t *testing.T) {
+	defaultMessage := "Blockchain Genesis: PoCW Subnet initialized with VLC consensus"
+	tests := []struct {
+		name      string
+		config    []GenesisConfig
+		want      string
+		wantClock string
+	}{
+		{"default", nil, defaultMessage, "{}"},
+		{"empty config", []GenesisConfig{{}}, defaultMessage, "{}"},
+		{"custom message", []GenesisConfig{{Message: "Genesis for subnet-eu"}}, "Genesis for subnet-eu", "{}"},
+		{"initial clock", []GenesisConfig{{InitialClock: map[int]int{1: 5, 2: 3}}}, defaultMessage, `{"1":5,"2":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sga := NewSubnetGraphAdapter("subnet-genesis", 1, "node-1", tt.config...)
+			// Participants start from the genesis clock so their events order after it
+			trackRounds(t, sga, sga.LatestClock(), 1)
+
+			events, err := sga.TopologicalOrder()
+			if err != nil {
+				t.Fatalf("TopologicalOrder() error: %v", err)
+			}
+			genesis := events[0]
+			if genesis.Name != "GenesisState" {
+				t.Fatalf("first event = %s, want GenesisState", genesis.Name)
+			}
+			if genesis.Value != tt.want {
+				t.Errorf("genesis value = %q, want %q", genesis.Value, tt.want)
+			}
+			if genesis.Clock != tt.wantClock {
+				t.Errorf("genesis clock = %s, want %s", genesis.Clock, tt.wantClock)
+			}
+		})
+	}
+}
+
+func TestLatestClockStartsAtGenesis(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-genesis", 1, "node-1", GenesisConfig{InitialClock: map[int]int{1: 5, 2: 3}})
+	if got := sga.LatestClock().String(); got != "{1:5,2:3}" {
+		t.Errorf("LatestClock() = %s, want {1:5,2:3}", got)
+	}
+	if got := fmt.Sprint(sga.GetSubnetStats().LatestVLCState); got != fmt.Sprint(map[int]int{1: 5, 2: 3}) {
+		t.Errorf("LatestVLCState = %s, want the genesis clock", got)
+	}
+}
+
+func TestEpochFinalizationLabelNamesEveryParticipant(t *testing.T) {
+	forgetParticipantNames(t, "subnet-label")
+	RegisterParticipantName("subnet-label", 3, "Validator-2")
+	sga := NewSubnetGraphAdapter("subnet-label", 1, "node-1")
+	clock := vlc.New()
+	clock.Inc(3) // A second validator has taken part in the epoch
+	trackRounds(t, sga, clock, 3)
+
+	want := "Epoch 1: Finalized with 3 rounds | VLC State: Miner=3, Validator-1=6, Validator-2=1"
+	for _, event := range sga.EventGraph.PendingEvents() {
+		if event.Name == "EpochFinalized" {
+			if event.Value != want {
+				t.Errorf("EpochFinalized value = %q, want %q", event.Value, want)
+			}
+			return
+		}
+	}
+	t.Fatal("no EpochFinalized event after three rounds")
+}
+
+// waitForEpoch polls until the adapter's current epoch reaches want or the timeout expires
+func waitForEpoch(sga *SubnetGraphAdapter, want int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if sga.GetSubnetStats().CurrentEpoch >= want {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestEpochTimerFinalizesPartialEpoch(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	sga := NewSubnetGraphAdapter("subnet-timer", 1, "node-1")
+	sga.SetClock(clock)
+	trackRounds(t, sga, vlc.New(), 1)
+
+	stop := sga.StartEpochTimer(10 * time.Millisecond)
+	defer close(stop)
+
+	clock.Advance(time.Hour)
+	if !waitForEpoch(sga, 2, 2*time.Second) {
+		t.Fatal("partial epoch was not finalized after its duration elapsed")
+	}
+	details, err := sga.GetEpochDetails(1)
+	if err != nil {
+		t.Fatalf("GetEpochDetails(1) error: %v", err)
+	}
+	if len(details.DetailedRounds) != 1 {
+		t.Errorf("finalized %d rounds, want 1", len(details.DetailedRounds))
+	}
+}
+
+func TestEpochTimerNonPositiveDurationIsNoOp(t *testing.T) {
+	for _, duration := range []time.Duration{0, -time.Second} {
+		clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		sga := NewSubnetGraphAdapter("subnet-timer", 1, "node-1")
+		sga.SetClock(clock)
+		trackRounds(t, sga, vlc.New(), 1)
+
+		stop := sga.StartEpochTimer(duration)
+		clock.Advance(time.Hour)
+		time.Sleep(10 * time.Millisecond)
+		close(stop)
+
+		if got := sga.GetSubnetStats().CurrentEpoch; got != 1 {
+			t.Errorf("StartEpochTimer(%v) finalized an epoch: CurrentEpoch = %d", duration, got)
+		}
+	}
+}
+
+func TestRefusedRoundDoesNotAdvanceChain(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-limit", 1, "node-1")
+	sga.SetMaxPendingEvents(3) // Genesis, UserInput and MinerOutput fit; the round event does not
+
+	var callbacks int
+	sga.SetRoundCompletedCallback(func(RoundResult) { callbacks++ })
+
+	clock := vlc.New()
+	if next := trackRound(t, sga, clock, "req-1", 1, true); next != "" {
+		t.Errorf("refused round returned next event %q, want \"\"", next)
+	}
+	if got := sga.GetPendingEventCount(); got != 3 {
+		t.Errorf("pending events = %d, want 3", got)
+	}
+	if stats := sga.GetSubnetStats(); stats.RoundsInEpoch != 0 || stats.EventsByType["RoundSuccess"] != 0 {
+		t.Errorf("refused round was counted: %d rounds in epoch, %d RoundSuccess events", stats.RoundsInEpoch, stats.EventsByType["RoundSuccess"])
+	}
+	if callbacks != 0 {
+		t.Errorf("round callback invoked %d times for a refused round", callbacks)
+	}
+
+	// Once events are accepted again, the next round chains from genesis rather than an empty ID
+	sga.SetMaxPendingEvents(0)
+	genesisID := sga.EventGraph.PendingEvents()[0].ID
+	clock.Inc(2)
+	inputID := sga.TrackUserInput("req-2", "next input", clock, "")
+	if parents := parentIDs(sga.EventGraph)[inputID]; len(parents) != 1 || parents[0] != genesisID {
+		t.Errorf("next UserInput parents = %v, want [%s]", parents, genesisID)
+	}
+}
+
+func TestRefusedConnectorKeepsRoundInChain(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-limit", 1, "node-1")
+	sga.SetMaxPendingEvents(4) // The round event fits but its NextRound connector does not
+
+	roundID := trackRound(t, sga, vlc.New(), "req-1", 1, true)
+	events := sga.EventGraph.PendingEvents()
+	if last := events[len(events)-1]; last.Name != "RoundSuccess" || roundID != last.ID {
+		t.Errorf("TrackRoundComplete() = %q, want the RoundSuccess event %q", roundID, last.ID)
+	}
+	if got := sga.GetSubnetStats().RoundsInEpoch; got != 1 {
+		t.Errorf("RoundsInEpoch = %d, want 1", got)
+	}
+}
+
+func TestPendingLimitAutoCommits(t *testing.T) {
+	fake := dgraphtest.Start(t)
+	if err := dgraph.InitDgraph(fake.Address()); err != nil {
+		t.Fatalf("InitDgraph() error = %v", err)
+	}
+	t.Cleanup(dgraph.Close)
+
+	sga := NewSubnetGraphAdapter("subnet-autocommit", 1, "node-1")
+	sga.SetMaxPendingEvents(4)
+	clock := vlc.New()
+
+	// Genesis, UserInput and MinerOutput leave no room for the round event and its
+	// NextRound connector, so they are committed first
+	if next := trackRound(t, sga, clock, "req-1", 1, true); next == "" {
+		t.Fatal("round refused although Dgraph accepts commits")
+	}
+	if got := len(fake.Committed()); got != 3 {
+		t.Errorf("committed %d events after the first round, want 3", got)
+	}
+	if got := sga.GetPendingEventCount(); got != 2 {
+		t.Errorf("pending events = %d after the first round, want 2", got)
+	}
+
+	for i := 2; i <= 3; i++ {
+		if next := trackRound(t, sga, clock, fmt.Sprintf("req-%d", i), i, true); next == "" {
+			t.Fatalf("round %d refused although Dgraph accepts commits", i)
+		}
+		if got := sga.GetPendingEventCount(); got > 4 {
+			t.Fatalf("pending events = %d after round %d, exceeding the limit of 4", got, i)
+		}
+	}
+	if got := sga.GetSubnetStats().CurrentEpoch; got != 2 {
+		t.Errorf("CurrentEpoch = %d, want 2 after three rounds", got)
+	}
+
+	// Every event reaches Dgraph, with parents committed before their children
+	if err := sga.CommitGraph(); err != nil {
+		t.Fatalf("CommitGraph() error = %v", err)
+	}
+	if got := sga.GetPendingEventCount(); got != 0 {
+		t.Errorf("pending events = %d after CommitGraph, want 0", got)
+	}
+	if got := len(fake.Committed()); got != 1+3*4 {
+		t.Errorf("committed %d events, want genesis plus 4 per round", got)
+	}
+}
+
+func TestSignEpochPayloadKnownVector(t *testing.T) {
+	// Widely published HMAC-SHA256 example vector
+	got := signEpochPayload("key", []byte("The quick brown fox jumps over the lazy dog"))
+	want := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Errorf("signEpochPayload() = %s, want %s", got, want)
+	}
+}
+
+// bridgeSubmission is one epoch submission received by a test bridge
+type bridgeSubmission struct {
+	body      []byte
+	signature string
+	hasHeader bool
+}
+
+// newTestBridge starts a bridge endpoint that reports each submission on the returned channel
+func newTestBridge(t *testing.T, status int) (*httptest.Server, chan bridgeSubmission) {
+	t.Helper()
+	submissions := make(chan bridgeSubmission, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, hasHeader := r.Header["X-Epoch-Signature"]
+		submissions <- bridgeSubmission{body: body, signature: r.Header.Get("X-Epoch-Signature"), hasHeader: hasHeader}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, submissions
+}
+
+// waitForSubmission returns the next bridge submission or fails the test after a timeout
+func waitForSubmission(t *testing.T, submissions chan bridgeSubmission) bridgeSubmission {
+	t.Helper()
+	select {
+	case submission := <-submissions:
+		return submission
+	case <-time.After(5 * time.Second):
+		t.Fatal("no epoch submitted to the bridge")
+		return bridgeSubmission{}
+	}
+}
+
+func TestEpochSubmissionSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{"signed", "bridge-secret"},
+		{"unsigned", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, submissions := newTestBridge(t, http.StatusOK)
+			sga := NewSubnetGraphAdapter("subnet-bridge", 1, "node-1")
+			sga.SetBridgeURL(server.URL)
+			sga.SetBridgeSecret(tt.secret)
+			trackRounds(t, sga, vlc.New(), 3)
+
+			submission := waitForSubmission(t, submissions)
+			if tt.secret == "" {
+				if submission.hasHeader {
+					t.Errorf("unsigned submission has X-Epoch-Signature %q", submission.signature)
+				}
+				return
+			}
+
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write(submission.body)
+			if want := hex.EncodeToString(mac.Sum(nil)); submission.signature != want {
+				t.Errorf("X-Epoch-Signature = %q, want %q", submission.signature, want)
+			}
+		})
+	}
+}
+
+func TestEpochMetricsWithFailingBridge(t *testing.T) {
+	server, submissions := newTestBridge(t, http.StatusInternalServerError)
+	sga := NewSubnetGraphAdapter("subnet-metrics", 1, "node-1")
+	sga.SetBridgeURL(server.URL)
+
+	fallbacks := make(chan int, 10)
+	sga.SetEpochFinalizedCallback(func(epochNumber int, subnetID string, epochData *EpochData) {
+		fallbacks <- epochNumber
+	})
+	trackRounds(t, sga, vlc.New(), 6) // Two epochs
+
+	for i := 0; i < 2; i++ {
+		waitForSubmission(t, submissions)
+		select {
+		case <-fallbacks:
+		case <-time.After(5 * time.Second):
+			t.Fatal("failed bridge submission did not fall back to the callback")
+		}
+	}
+
+	want := EpochMetrics{EpochsFinalized: 2, BridgeFailed: 2, CallbacksInvoked: 2, CallbackFallbacks: 2}
+	if got := sga.GetEpochMetrics(); got != want {
+		t.Errorf("GetEpochMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEpochMetricsWithWorkingBridge(t *testing.T) {
+	server, submissions := newTestBridge(t, http.StatusOK)
+	sga := NewSubnetGraphAdapter("subnet-metrics", 1, "node-1")
+	sga.SetBridgeURL(server.URL)
+	trackRounds(t, sga, vlc.New(), 3)
+	waitForSubmission(t, submissions)
+
+	want := EpochMetrics{EpochsFinalized: 1, BridgeSucceeded: 1}
+	deadline := time.Now().Add(5 * time.Second)
+	for sga.GetEpochMetrics() != want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond) // The success is recorded after the bridge responds
+	}
+	if got := sga.GetEpochMetrics(); got != want {
+		t.Errorf("GetEpochMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRoundCompletedCallback(t *testing.T) {
+	sga := NewSubnetGraphAdapter("subnet-callback", 1, "node-1")
+	var results []RoundResult
+	sga.SetRoundCompletedCallback(func(result RoundResult) {
+		// The callback runs outside the adapter lock, so it may query the adapter
+		sga.GetSubnetStats()
+		results = append(results, result)
+	})
+
+	clock := vlc.New()
+	trackRound(t, sga, clock, "req-ok", 1, true)
+	trackRound(t, sga, clock, "req-bad", 2, false)
+
+	if len(results) != 2 {
+		t.Fatalf("callback fired %d times, want 2", len(results))
+	}
+	success, failure := results[0], results[1]
+
+	if success.SubnetID != "subnet-callback" || success.RequestID != "req-ok" || success.RoundNumber != 1 {
+		t.Errorf("success round identity = %s/%s/%d", success.SubnetID, success.RequestID, success.RoundNumber)
+	}
+	if !success.Success || !success.ConsensusAccepted || !success.UserAccept ||
+		success.ConsensusResult != "ACCEPTED" || success.FinalResult != "OUTPUT DELIVERED TO USER" {
+		t.Errorf("success round = %+v", success)
+	}
+	if fmt.Sprint(success.VLCClockState) != fmt.Sprint(map[int]int{1: 1, 2: 2}) {
+		t.Errorf("success VLCClockState = %v, want map[1:1 2:2]", success.VLCClockState)
+	}
+
+	if failure.RequestID != "req-bad" || failure.Success || failure.ConsensusAccepted || failure.UserAccept ||
+		failure.ConsensusResult != "REJECTED" || failure.FinalResult != "OUTPUT REJECTED" {
+		t.Errorf("failure round = %+v", failure)
+	}
+	if fmt.Sprint(failure.VLCClockState) != fmt.Sprint(map[int]int{1: 2, 2: 4}) {
+		t.Errorf("failure VLCClockState = %v, want map[1:2 2:4]", failure.VLCClockState)
+	}
+
+	names := make(map[string]string)
+	for _, event := range sga.EventGraph.PendingEvents() {
+		names[event.ID] = event.Name
+	}
+	if names[success.EventID] != "RoundSuccess" || names[failure.EventID] != "RoundFailed" {
+		t.Errorf("callback event IDs %s/%s name %q/%q, want RoundSuccess/RoundFailed",
+			success.EventID, failure.EventID, names[success.EventID], names[failure.EventID])
+	}
+}
+
+// trackUserRejectedRound records a round validators accepted but the user rejected
+func trackUserRejectedRound(sga *SubnetGraphAdapter, clock *vlc.Clock, requestID string, roundNum int) {
+	clock.Inc(2)
+	inputEventID := sga.TrackUserInput(requestID, "input for "+requestID, clock, "")
+	clock.Inc(1)
+	minerEventID := sga.TrackMinerResponse(requestID, &MinerResponseMessage{
+		OutputType:  OutputReady,
+		Output:      "output for " + requestID,
+		VLCClock:    clock.Copy(),
+		InputNumber: roundNum,
+	}, inputEventID)
+	clock.Inc(2)
+	sga.TrackRoundComplete(requestID, roundNum, clock, "ACCEPTED", true, "not what I asked for", false,
+		"OUTPUT REJECTED BY USER (despite validator acceptance)", minerEventID)
+}
+
+func TestUserRejectionUnderRewardPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		requireUser   bool
+		wantSuccess   bool
+		wantEventName string
+	}{
+		{"user acceptance required", true, false, "RoundFailed"},
+		{"consensus alone", false, true, "RoundSuccess"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sga := NewSubnetGraphAdapter("subnet-reward", 1, "node-1")
+			sga.SetRewardRequiresUserAcceptance(tt.requireUser)
+			sga.SetMinSuccessfulRoundsForMining(1)
+
+			var results []RoundResult
+			sga.SetRoundCompletedCallback(func(result RoundResult) { results = append(results, result) })
+			epochs := make(chan *EpochData, 1)
+			sga.SetEpochFinalizedCallback(func(epochNumber int, subnetID string, epochData *EpochData) {
+				epochs <- epochData
+			})
+
+			clock := vlc.New()
+			for i := 1; i <= 3; i++ {
+				trackUserRejectedRound(sga, clock, fmt.Sprintf("req-%d", i), i)
+			}
+
+			if len(results) != 3 {
+				t.Fatalf("round callback fired %d times, want 3", len(results))
+			}
+			for i, result := range results {
+				if result.Success != tt.wantSuccess {
+					t.Errorf("round %d Success = %v, want %v", i+1, result.Success, tt.wantSuccess)
+				}
+			}
+			names := make(map[string]string)
+			for _, event := range sga.EventGraph.PendingEvents() {
+				names[event.ID] = event.Name
+			}
+			for _, result := range results {
+				if names[result.EventID] != tt.wantEventName {
+					t.Errorf("round event %s is %q, want %q", result.EventID, names[result.EventID], tt.wantEventName)
+				}
+			}
+
+			if !tt.wantSuccess {
+				if metrics := sga.GetEpochMetrics(); metrics.SkippedLowValue != 1 || metrics.CallbacksInvoked != 0 {
+					t.Errorf("metrics = %+v, want the epoch skipped for mining", metrics)
+				}
+				select {
+				case <-epochs:
+					t.Error("epoch without user-accepted rounds was submitted for mining")
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case epoch := <-epochs:
+				for i, round := range epoch.DetailedRounds {
+					if !round.Success || round.UserAccept {
+						t.Errorf("submitted round %d = Success %v, UserAccept %v; want true, false", i+1, round.Success, round.UserAccept)
+					}
+				}
+			case <-time.After(time.Second):
+				t.Fatal("epoch was not submitted for mining")
+			}
+		})
+	}
+}
+
+func TestMinSuccessfulRoundsForMining(t *testing.T) {
+	tests := []struct {
+		name       string
+		minRounds  int
+		accepted   []bool
+		wantSubmit bool
+	}{
+		{"no threshold", 0, []bool{false, false, false}, true},
+		{"above threshold", 2, []bool{true, true, true}, true},
+		{"at threshold", 2, []bool{true, false, true}, true},
+		{"below threshold", 2, []bool{true, false, false}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sga := NewSubnetGraphAdapter("subnet-threshold", 1, "node-1")
+			sga.SetMinSuccessfulRoundsForMining(tt.minRounds)
+			submitted := make(chan int, 1)
+			sga.SetEpochFinalizedCallback(func(epochNumber int, subnetID string, epochData *EpochData) {
+				submitted <- epochNumber
+			})
+
+			clock := vlc.New()
+			for i, accept := range tt.accepted {
+				trackRound(t, sga, clock, fmt.Sprintf("req-%d", i+1), i+1, accept)
+			}
+
+			// Skipped epochs are still finalized and stored
+			if _, err := sga.GetEpochDetails(1); err != nil {
+				t.Errorf("GetEpochDetails(1) error = %v", err)
+			}
+
+			if tt.wantSubmit {
+				select {
+				case <-submitted:
+				case <-time.After(time.Second):
+					t.Fatal("epoch was not submitted")
+				}
+				if got := sga.GetEpochMetrics().SkippedLowValue; got != 0 {
+					t.Errorf("SkippedLowValue = %d, want 0", got)
+				}
+				return
+			}
+
+			select {
+			case <-submitted:
+				t.Error("low-value epoch was submitted")
+			case <-time.After(50 * time.Millisecond):
+			}
+			if metrics := sga.GetEpochMetrics(); metrics.SkippedLowValue != 1 || metrics.CallbacksInvoked != 0 {
+				t.Errorf("metrics = %+v, want one skipped epoch and no callbacks", metrics)
+			}
+		})
+	}
+}
+
+func TestFinalizeExpiredEpochFollowsFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	sga := NewSubnetGraphAdapter("subnet-clock", 1, "node-1")
+	sga.SetClock(clock)
+	vlcClock := vlc.New()
+	chainTip := trackRound(t, sga, vlcClock, "req-1", 1, true)
+
+	clock.Advance(time.Hour - time.Second)
+	sga.finalizeExpiredEpoch(time.Hour)
+	if got := sga.GetSubnetStats().CurrentEpoch; got != 1 {
+		t.Fatalf("epoch finalized before its duration elapsed: CurrentEpoch = %d", got)
+	}
+
+	clock.Advance(time.Second)
+	sga.finalizeExpiredEpoch(time.Hour)
+	if got := sga.GetSubnetStats().CurrentEpoch; got != 2 {
+		t.Fatalf("epoch not finalized once its duration elapsed: CurrentEpoch = %d", got)
+	}
+	epoch, err := sga.GetEpochDetails(1)
+	if err != nil {
+		t.Fatalf("GetEpochDetails(1) error = %v", err)
+	}
+	if want := start.Add(time.Hour).Unix(); epoch.FinalizedAt != want {
+		t.Errorf("FinalizedAt = %d, want %d", epoch.FinalizedAt, want)
+	}
+	if len(epoch.CompletedRounds) != 1 || epoch.ParentRoundEventID != epoch.CompletedRounds[0] {
+		t.Errorf("ParentRoundEventID = %s, want the last round %v", epoch.ParentRoundEventID, epoch.CompletedRounds)
+	}
+
+	// The forced finalization continues the chain from the round's NextRound connector
+	var finalized models.Event
+	for _, event := range sga.EventGraph.PendingEvents() {
+		if event.Name == "EpochFinalized" {
+			finalized = event
+		}
+	}
+	if parents := parentIDs(sga.EventGraph)[finalized.ID]; len(parents) != 1 || parents[0] != chainTip {
+		t.Errorf("EpochFinalized parents = %v, want the chain tip [%s]", parents, chainTip)
+	}
+
+	// The next epoch's window starts when the previous one was finalized
+	trackRound(t, sga, vlcClock, "req-2", 2, true)
+	clock.Advance(30 * time.Minute)
+	sga.finalizeExpiredEpoch(time.Hour)
+	if got := sga.GetSubnetStats().CurrentEpoch; got != 2 {
+		t.Errorf("second epoch finalized after 30 minutes: CurrentEpoch = %d", got)
+	}
+}
+
+func TestEpochTimestampUsesAdapterClock(t *testing.T) {
+	finalizedAt := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	server, submissions := newTestBridge(t, http.StatusOK)
+	sga := NewSubnetGraphAdapter("subnet-clock", 1, "node-1")
+	sga.SetClock(NewFakeClock(finalizedAt))
+	sga.SetBridgeURL(server.URL)
+
+	trackRounds(t, sga, vlc.New(), 3)
+
+	epoch, err := sga.GetEpochDetails(1)
+	if err != nil {
+		t.Fatalf("GetEpochDetails(1) error = %v", err)
+	}
+	if epoch.FinalizedAt != finalizedAt.Unix() {
+		t.Errorf("FinalizedAt = %d, want %d", epoch.FinalizedAt, finalizedAt.Unix())
+	}
+
+	var payload struct {
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := json.Unmarshal(waitForSubmission(t, submissions).body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Timestamp != finalizedAt.Unix() {
+		t.Errorf("bridge timestamp = %d, want %d", payload.Timestamp, finalizedAt.Unix())
+	}
+}
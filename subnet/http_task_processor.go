@@ -0,0 +1,161 @@
+// Package subnet - HTTP Task Processor
+//
+// This file implements HTTPTaskProcessor, a TaskProcessor that delegates task
+// processing to an external model endpoint (e.g. an LLM inference service) over HTTP.
+// It lets the same CoreMiner run real intelligence work instead of the demo's
+// hardcoded responses.
+package subnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultModelFailureQuestion is asked when the model endpoint cannot process a request,
+// giving the user a chance to restate the task instead of validating an error.
+const DefaultModelFailureQuestion = "The model could not process this request. Could you rephrase it or add more detail?"
+
+// HTTPTaskProcessorConfig configures the external model endpoint
+type HTTPTaskProcessorConfig struct {
+	Endpoint        string        // URL that receives task processing requests via POST
+	APIKey          string        // Optional bearer token sent in the Authorization header
+	Timeout         time.Duration // Per-request timeout (defaults to 30s)
+	FailureQuestion string        // Info request returned when the model request fails
+}
+
+// modelRequest is the JSON body sent to the model endpoint
+type modelRequest struct {
+	Input          string `json:"input"`
+	InputNumber    int    `json:"input_number"`
+	AdditionalInfo string `json:"additional_info,omitempty"`
+}
+
+// modelResponse is the JSON body expected from the model endpoint.
+// OutputType must be "output_ready" or "need_more_info".
+type modelResponse struct {
+	OutputType  MinerOutputType `json:"output_type"`
+	Output      string          `json:"output"`
+	InfoRequest string          `json:"info_request"`
+}
+
+// HTTPTaskProcessor implements TaskProcessor by POSTing user input to an external model API.
+//
+// It implements FollowUpTaskProcessor, so CoreMiner calls ProcessFollowUp after the
+// user answers and the model may ask another question.
+//
+// Failure Handling:
+//   - ProcessTask and ProcessFollowUp failures become NeedMoreInfo with a safe
+//     clarification question, so a failing endpoint ends the round through the
+//     coordinator's info request limit instead of producing an output
+//   - ProcessAdditionalInfo, unused by CoreMiner, returns an error message as its output
+type HTTPTaskProcessor struct {
+	config HTTPTaskProcessorConfig
+	client *http.Client
+}
+
+// NewHTTPTaskProcessor creates a task processor backed by an external model endpoint
+func NewHTTPTaskProcessor(config HTTPTaskProcessorConfig) *HTTPTaskProcessor {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.FailureQuestion == "" {
+		config.FailureQuestion = DefaultModelFailureQuestion
+	}
+	return &HTTPTaskProcessor{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// ProcessTask sends the initial user input to the model endpoint
+func (p *HTTPTaskProcessor) ProcessTask(input string, inputNumber int) (MinerOutputType, string, string) {
+	resp, err := p.callModel(modelRequest{Input: input, InputNumber: inputNumber})
+	if err != nil {
+		fmt.Printf("Miner: Input %d - Model request failed: %v\n", inputNumber, err)
+		return NeedMoreInfo, "", p.config.FailureQuestion
+	}
+
+	if resp.OutputType == NeedMoreInfo {
+		return NeedMoreInfo, "", resp.InfoRequest
+	}
+	return OutputReady, resp.Output, ""
+}
+
+// ProcessFollowUp sends the original input together with all additional context gathered
+// so far. The model may answer with another info request.
+func (p *HTTPTaskProcessor) ProcessFollowUp(originalInput string, additionalInfo string, inputNumber int) (MinerOutputType, string, string) {
+	resp, err := p.callModel(modelRequest{Input: originalInput, InputNumber: inputNumber, AdditionalInfo: additionalInfo})
+	if err != nil {
+		fmt.Printf("Miner: Input %d - Model request with additional info failed: %v\n", inputNumber, err)
+		return NeedMoreInfo, "", p.config.FailureQuestion
+	}
+
+	if resp.OutputType == NeedMoreInfo {
+		return NeedMoreInfo, "", resp.InfoRequest
+	}
+	return OutputReady, resp.Output, ""
+}
+
+// ProcessAdditionalInfo satisfies TaskProcessor for callers that need a single final
+// output. CoreMiner uses ProcessFollowUp instead. The model must produce a final
+// output at this stage; an info request is treated as a failure.
+func (p *HTTPTaskProcessor) ProcessAdditionalInfo(originalInput string, additionalInfo string, inputNumber int) string {
+	resp, err := p.callModel(modelRequest{Input: originalInput, InputNumber: inputNumber, AdditionalInfo: additionalInfo})
+	if err == nil && resp.OutputType == NeedMoreInfo {
+		err = fmt.Errorf("model requested more info after additional context was provided")
+	}
+	if err != nil {
+		fmt.Printf("Miner: Input %d - Model request with additional info failed: %v\n", inputNumber, err)
+		return fmt.Sprintf("Error: model could not process input %d: %v", inputNumber, err)
+	}
+	return resp.Output
+}
+
+// callModel performs the HTTP round-trip and validates the response shape
+func (p *HTTPTaskProcessor) callModel(request modelRequest) (*modelResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal model request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", p.config.Endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach model endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("model endpoint returned error status: %d", resp.StatusCode)
+	}
+
+	var result modelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode model response: %v", err)
+	}
+
+	switch result.OutputType {
+	case OutputReady:
+		if result.Output == "" {
+			return nil, fmt.Errorf("model response is missing output")
+		}
+	case NeedMoreInfo:
+		if result.InfoRequest == "" {
+			return nil, fmt.Errorf("model response is missing info_request")
+		}
+	default:
+		return nil, fmt.Errorf("unknown model output type %q", result.OutputType)
+	}
+	return &result, nil
+}
@@ -0,0 +1,119 @@
+package subnet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newModelServer starts a model endpoint that records each request and replies with
+// the given status and JSON body
+func newModelServer(t *testing.T, status int, reply string, requests *[]modelRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request modelRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("model endpoint received invalid JSON: %v", err)
+		}
+		if requests != nil {
+			*requests = append(*requests, request)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPTaskProcessorProcessTask(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		reply           string
+		wantType        MinerOutputType
+		wantOutput      string
+		wantInfoRequest string
+	}{
+		{"output ready", http.StatusOK, `{"output_type": "output_ready", "output": "Q4 trends"}`, OutputReady, "Q4 trends", ""},
+		{"needs more info", http.StatusOK, `{"output_type": "need_more_info", "info_request": "Which region?"}`, NeedMoreInfo, "", "Which region?"},
+		{"server error", http.StatusInternalServerError, `{}`, NeedMoreInfo, "", DefaultModelFailureQuestion},
+		{"missing output", http.StatusOK, `{"output_type": "output_ready"}`, NeedMoreInfo, "", DefaultModelFailureQuestion},
+		{"unknown type", http.StatusOK, `{"output_type": "done", "output": "x"}`, NeedMoreInfo, "", DefaultModelFailureQuestion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []modelRequest
+			server := newModelServer(t, tt.status, tt.reply, &requests)
+			processor := NewHTTPTaskProcessor(HTTPTaskProcessorConfig{Endpoint: server.URL})
+
+			outputType, output, infoRequest := processor.ProcessTask("Analyze trends", 1)
+			if outputType != tt.wantType || output != tt.wantOutput || infoRequest != tt.wantInfoRequest {
+				t.Errorf("ProcessTask() = (%s, %q, %q), want (%s, %q, %q)",
+					outputType, output, infoRequest, tt.wantType, tt.wantOutput, tt.wantInfoRequest)
+			}
+			if len(requests) != 1 || requests[0].Input != "Analyze trends" || requests[0].InputNumber != 1 {
+				t.Errorf("model requests = %+v", requests)
+			}
+		})
+	}
+}
+
+func TestHTTPTaskProcessorProcessFollowUp(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		reply           string
+		wantType        MinerOutputType
+		wantOutput      string
+		wantInfoRequest string
+	}{
+		{"output ready", http.StatusOK, `{"output_type": "output_ready", "output": "EU Q4 trends"}`, OutputReady, "EU Q4 trends", ""},
+		{"asks again", http.StatusOK, `{"output_type": "need_more_info", "info_request": "Which quarter?"}`, NeedMoreInfo, "", "Which quarter?"},
+		{"server error", http.StatusBadGateway, `{}`, NeedMoreInfo, "", "Please restate the task."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []modelRequest
+			server := newModelServer(t, tt.status, tt.reply, &requests)
+			processor := NewHTTPTaskProcessor(HTTPTaskProcessorConfig{Endpoint: server.URL, FailureQuestion: "Please restate the task."})
+
+			outputType, output, infoRequest := processor.ProcessFollowUp("Analyze trends", "Europe only", 3)
+			if outputType != tt.wantType || output != tt.wantOutput || infoRequest != tt.wantInfoRequest {
+				t.Errorf("ProcessFollowUp() = (%s, %q, %q), want (%s, %q, %q)",
+					outputType, output, infoRequest, tt.wantType, tt.wantOutput, tt.wantInfoRequest)
+			}
+			if len(requests) != 1 || requests[0].AdditionalInfo != "Europe only" || requests[0].InputNumber != 3 {
+				t.Errorf("model requests = %+v", requests)
+			}
+		})
+	}
+}
+
+func TestHTTPTaskProcessorSendsAPIKey(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"output_type": "output_ready", "output": "ok"}`))
+	}))
+	defer server.Close()
+
+	processor := NewHTTPTaskProcessor(HTTPTaskProcessorConfig{Endpoint: server.URL, APIKey: "secret-key"})
+	processor.ProcessTask("input", 1)
+	if authorization != "Bearer secret-key" {
+		t.Errorf("Authorization = %q, want Bearer secret-key", authorization)
+	}
+}
+
+func TestCoreMinerUsesFollowUpOnModelFailure(t *testing.T) {
+	server := newModelServer(t, http.StatusInternalServerError, `{}`, nil)
+	miner := NewCoreMiner("miner-http", "subnet-http", 1)
+	miner.SetTaskProcessor(NewHTTPTaskProcessor(HTTPTaskProcessorConfig{Endpoint: server.URL}))
+
+	response := miner.ProcessAdditionalInfo("Analyze trends", "Europe only", 1, "req-1")
+	if response.OutputType != NeedMoreInfo || response.Output != "" {
+		t.Errorf("failed follow-up = (%s, %q), want NeedMoreInfo without output", response.OutputType, response.Output)
+	}
+}
@@ -6,6 +6,9 @@
 package subnet
 
 import (
+	"fmt"
+
+	"github.com/hetu-project/Intelligence-KEY-Mining/consensus"
 	"github.com/hetu-project/Intelligence-KEY-Mining/vlc"
 )
 
@@ -92,16 +95,38 @@ type FinalOutputMessage struct {
 	Consensus    float64 `json:"consensus"` // Total acceptance weight
 }
 
+// TieBreakPolicy decides the outcome when accept and reject votes each hold
+// exactly half of the voting weight, so neither side reaches a >50% majority.
+type TieBreakPolicy string
+
+const (
+	RejectOnTie        TieBreakPolicy = "reject-on-tie"        // A tie rejects the output (default)
+	AcceptOnTie        TieBreakPolicy = "accept-on-tie"        // A tie accepts the output
+	HighestQualityWins TieBreakPolicy = "highest-quality-wins" // The side with the higher weighted mean quality wins
+)
+
+// ParseTieBreakPolicy converts a policy name into a TieBreakPolicy.
+// An empty name selects RejectOnTie.
+func ParseTieBreakPolicy(name string) (TieBreakPolicy, error) {
+	switch policy := TieBreakPolicy(name); policy {
+	case "":
+		return RejectOnTie, nil
+	case RejectOnTie, AcceptOnTie, HighestQualityWins:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown tie-break policy %q", name)
+	}
+}
+
 // QualityAssessment tracks and aggregates validator consensus on miner output quality.
-// Implements Byzantine Fault Tolerant (BFT) consensus by accumulating weighted votes.
-// Consensus is reached when sufficient validators have voted (determined by total weight).
+// Implements Byzantine Fault Tolerant (BFT) consensus by accumulating weighted votes
+// in a consensus.Tally. Consensus is reached when sufficient validators have voted
+// (determined by total weight).
 type QualityAssessment struct {
-	RequestID   string  // Unique identifier for the request being assessed
-	TotalWeight float64 // Sum of all validator weights that have voted
-	AcceptVotes float64 // Sum of weights from validators who accepted the output
-	RejectVotes float64 // Sum of weights from validators who rejected the output
-	VoteCount   int     // Total number of validator votes received
-	Consensus   bool    // Whether sufficient votes have been received for consensus
+	consensus.Tally                // Weighted accept/reject votes and quality scores
+	RequestID       string         // Unique identifier for the request being assessed
+	Consensus       bool           // Whether sufficient votes have been received for consensus
+	TieBreak        TieBreakPolicy // How an exact 50/50 split is decided (empty = RejectOnTie)
 }
 
 // AddVote incorporates a validator's vote into the consensus assessment.
@@ -110,23 +135,28 @@ type QualityAssessment struct {
 // Consensus Logic:
 //   - Consensus achieved when >50% of total voting weight participates
 //   - Acceptance requires >50% of participating weight to vote "accept"
+//   - An exact 50/50 split is also final and is decided by the TieBreak policy
 //   - This implements Byzantine Fault Tolerant consensus for quality assessment
 //
 // Parameters:
 //   - weight: Validator's voting weight (typically 1.0/N for N validators)
 //   - accept: Validator's decision (true = accept output, false = reject output)
 func (qa *QualityAssessment) AddVote(weight float64, accept bool) {
-	qa.TotalWeight += weight
-	qa.VoteCount++
+	qa.AddQualityVote(weight, accept, 0)
+}
 
-	if accept {
-		qa.AcceptVotes += weight
-	} else {
-		qa.RejectVotes += weight
-	}
+// AddQualityVote records a vote together with the validator's quality score,
+// which HighestQualityWins uses to break exact ties.
+func (qa *QualityAssessment) AddQualityVote(weight float64, accept bool, quality float64) {
+	qa.Add(weight, accept, quality)
 
-	// Consensus reached if > 50% weight votes (BFT threshold)
-	qa.Consensus = qa.AcceptVotes > 0.5 || qa.RejectVotes > 0.5
+	// Consensus reached if > 50% weight votes (BFT threshold) or the vote is an exact tie
+	qa.Consensus = qa.Result(consensus.DefaultThreshold, 0) != consensus.Pending
+}
+
+// IsTie reports whether accept and reject votes each hold exactly half of the voting weight
+func (qa *QualityAssessment) IsTie() bool {
+	return qa.Result(consensus.DefaultThreshold, 0) == consensus.Tied
 }
 
 // IsAccepted returns true if the consensus assessment indicates output acceptance.
@@ -134,7 +164,39 @@ func (qa *QualityAssessment) AddVote(weight float64, accept bool) {
 //
 // Returns true only if:
 //   1. Consensus threshold reached (>50% validator weight participated)
-//   2. Majority of participating validators voted to accept (>50% of votes)
+//   2. Majority of participating validators voted to accept (>50% of votes),
+//      or the vote is an exact tie that the TieBreak policy resolves as accepted
 func (qa *QualityAssessment) IsAccepted() bool {
-	return qa.Consensus && qa.AcceptVotes > 0.5
+	if !qa.Consensus {
+		return false
+	}
+	switch qa.Result(consensus.DefaultThreshold, 0) {
+	case consensus.Accepted:
+		return true
+	case consensus.Tied:
+		return qa.resolveTie()
+	default:
+		return false
+	}
+}
+
+// HasQuorum reports whether enough validators voted for the outcome to be valid:
+// at least minVotes votes carrying at least quorumWeight of the voting weight.
+// Zero values disable the corresponding requirement.
+func (qa *QualityAssessment) HasQuorum(minVotes int, quorumWeight float64) bool {
+	return qa.VoteCount >= minVotes && qa.Result(consensus.DefaultThreshold, quorumWeight) != consensus.NoQuorum
+}
+
+// resolveTie applies the TieBreak policy to an exact 50/50 split
+func (qa *QualityAssessment) resolveTie() bool {
+	switch qa.TieBreak {
+	case AcceptOnTie:
+		return true
+	case HighestQualityWins:
+		// Both sides hold equal weight, so comparing the weighted sums compares
+		// the weighted mean quality; equal quality falls back to rejection
+		return qa.AcceptQuality > qa.RejectQuality
+	default:
+		return false
+	}
 }
@@ -0,0 +1,107 @@
+package subnet
+
+import "testing"
+
+// testVote is one validator's weighted vote and quality score
+type testVote struct {
+	weight  float64
+	accept  bool
+	quality float64
+}
+
+// assessmentOf builds an assessment with the tie-break policy from the votes
+func assessmentOf(tieBreak TieBreakPolicy, votes ...testVote) *QualityAssessment {
+	qa := &QualityAssessment{RequestID: "req-1", TieBreak: tieBreak}
+	for _, vote := range votes {
+		qa.AddQualityVote(vote.weight, vote.accept, vote.quality)
+	}
+	return qa
+}
+
+func TestExactTieFollowsTieBreakPolicy(t *testing.T) {
+	betterAccepts := []testVote{{0.25, true, 0.9}, {0.25, true, 0.8}, {0.25, false, 0.3}, {0.25, false, 0.2}}
+	betterRejects := []testVote{{0.25, true, 0.3}, {0.25, true, 0.2}, {0.25, false, 0.9}, {0.25, false, 0.8}}
+	equalQuality := []testVote{{0.5, true, 0.6}, {0.5, false, 0.6}}
+
+	tests := []struct {
+		name     string
+		tieBreak TieBreakPolicy
+		votes    []testVote
+		want     bool
+	}{
+		{"default rejects", "", betterAccepts, false},
+		{"reject on tie", RejectOnTie, betterAccepts, false},
+		{"accept on tie", AcceptOnTie, betterRejects, true},
+		{"higher accept quality wins", HighestQualityWins, betterAccepts, true},
+		{"higher reject quality wins", HighestQualityWins, betterRejects, false},
+		{"equal quality rejects", HighestQualityWins, equalQuality, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qa := assessmentOf(tt.tieBreak, tt.votes...)
+			if !qa.IsTie() || !qa.Consensus {
+				t.Fatalf("IsTie/Consensus = %v/%v, want an exact tie with consensus", qa.IsTie(), qa.Consensus)
+			}
+			if got := qa.IsAccepted(); got != tt.want {
+				t.Errorf("IsAccepted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMajorityIgnoresTieBreakPolicy(t *testing.T) {
+	for _, tieBreak := range []TieBreakPolicy{RejectOnTie, AcceptOnTie, HighestQualityWins} {
+		accepted := assessmentOf(tieBreak, testVote{0.6, true, 0.1}, testVote{0.4, false, 0.9})
+		if accepted.IsTie() || !accepted.IsAccepted() {
+			t.Errorf("%s: 60%% accept majority was not accepted", tieBreak)
+		}
+		rejected := assessmentOf(tieBreak, testVote{0.4, true, 0.9}, testVote{0.6, false, 0.1})
+		if rejected.IsTie() || rejected.IsAccepted() {
+			t.Errorf("%s: 60%% reject majority was accepted", tieBreak)
+		}
+	}
+}
+
+func TestParseTieBreakPolicy(t *testing.T) {
+	for name, want := range map[string]TieBreakPolicy{
+		"":                     RejectOnTie,
+		"reject-on-tie":        RejectOnTie,
+		"accept-on-tie":        AcceptOnTie,
+		"highest-quality-wins": HighestQualityWins,
+	} {
+		if got, err := ParseTieBreakPolicy(name); err != nil || got != want {
+			t.Errorf("ParseTieBreakPolicy(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseTieBreakPolicy("coin-flip"); err == nil {
+		t.Error("ParseTieBreakPolicy accepted an unknown policy")
+	}
+}
+
+func TestHasQuorum(t *testing.T) {
+	// Two of four equally weighted validators voted; the other two returned no vote
+	qa := assessmentOf(RejectOnTie, testVote{0.25, true, 0.9}, testVote{0.25, true, 0.8})
+
+	tests := []struct {
+		name         string
+		minVotes     int
+		quorumWeight float64
+		want         bool
+	}{
+		{"no requirement", 0, 0, true},
+		{"enough votes", 2, 0, true},
+		{"too few votes", 3, 0, false},
+		{"enough weight", 0, 0.5, true},
+		{"too little weight", 0, 0.75, false},
+		{"votes met but weight not", 2, 0.75, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := qa.HasQuorum(tt.minVotes, tt.quorumWeight); got != tt.want {
+				t.Errorf("HasQuorum(%d, %v) = %v, want %v", tt.minVotes, tt.quorumWeight, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Bridge CORS Test - checks ALLOWED_ORIGINS handling of the per-epoch bridge server
+const assert = require('assert');
+const http = require('http');
+const { parseAllowedOrigins, matchOrigin, applyCors } = require('../bridge-cors');
+
+// Start a server that applies the bridge's CORS handling for the given ALLOWED_ORIGINS value
+function startServer(allowedOrigins) {
+    const allowed = parseAllowedOrigins(allowedOrigins);
+    const server = http.createServer((req, res) => {
+        applyCors(allowed, req, res);
+        res.writeHead(200);
+        res.end();
+    });
+    return new Promise(resolve => server.listen(0, '127.0.0.1', () => resolve(server)));
+}
+
+// Send a request with the Origin header and return the response headers
+function requestHeaders(server, origin) {
+    const headers = origin ? { Origin: origin } : {};
+    return new Promise((resolve, reject) => {
+        const req = http.request({ host: '127.0.0.1', port: server.address().port, method: 'OPTIONS', headers }, res => {
+            res.resume();
+            res.on('end', () => resolve(res.headers));
+        });
+        req.on('error', reject);
+        req.end();
+    });
+}
+
+async function main() {
+    assert.deepStrictEqual(parseAllowedOrigins(' https://a.example.com, ,https://*.example.org '), ['https://a.example.com', 'https://*.example.org']);
+    assert.deepStrictEqual(parseAllowedOrigins(undefined), []);
+
+    const allowed = ['https://app.example.com', 'https://*.example.org'];
+    assert.strictEqual(matchOrigin([], 'https://evil.test'), '*');
+    assert.strictEqual(matchOrigin(allowed, 'https://app.example.com'), 'https://app.example.com');
+    assert.strictEqual(matchOrigin(allowed, 'https://dash.example.org'), 'https://dash.example.org');
+    assert.strictEqual(matchOrigin(allowed, 'https://example.org'), '');
+    assert.strictEqual(matchOrigin(allowed, 'http://dash.example.org'), '');
+    assert.strictEqual(matchOrigin(allowed, 'https://evil.test'), '');
+    assert.strictEqual(matchOrigin(allowed, 'https://evilexample.org'), '');
+    assert.strictEqual(matchOrigin(['*'], 'https://evil.test'), '*');
+    assert.strictEqual(matchOrigin(allowed, undefined), '');
+
+    const server = await startServer('https://app.example.com,https://*.example.org');
+    try {
+        let headers = await requestHeaders(server, 'https://app.example.com');
+        assert.strictEqual(headers['access-control-allow-origin'], 'https://app.example.com');
+        assert.strictEqual(headers['vary'], 'Origin');
+        assert.ok(headers['access-control-allow-headers'].includes('X-Epoch-Signature'));
+
+        for (const origin of ['https://evil.test', 'https://example.org', undefined]) {
+            headers = await requestHeaders(server, origin);
+            for (const name of ['access-control-allow-origin', 'access-control-allow-methods', 'access-control-allow-headers']) {
+                assert.strictEqual(headers[name], undefined, `${name} sent for disallowed origin ${origin}`);
+            }
+        }
+    } finally {
+        server.close();
+    }
+
+    const openServer = await startServer('');
+    try {
+        const headers = await requestHeaders(openServer, 'https://anything.test');
+        assert.strictEqual(headers['access-control-allow-origin'], '*');
+        assert.strictEqual(headers['vary'], undefined);
+    } finally {
+        openServer.close();
+    }
+
+    console.log('✅ Bridge CORS tests passed');
+}
+
+main().catch(err => {
+    console.error('❌ Bridge CORS tests failed:', err.message);
+    process.exit(1);
+});
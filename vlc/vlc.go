@@ -3,7 +3,8 @@ package vlc
 import (
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"sort"
+	"strings"
 )
 
 // Clock represents a verifiable logical clock
@@ -65,6 +66,35 @@ func (c *Clock) Merge(others []*Clock) {
 	}
 }
 
+// Prune drops entries for participants that are not in the active set.
+//
+// Pruning forgets history, so it is only causally safe when every peer prunes
+// the same participants at the same point (e.g. at an epoch boundary). A missing
+// entry reads as zero, so a pruned clock compared against an unpruned copy of
+// itself reports Less rather than Equal.
+func (c *Clock) Prune(active []uint64) {
+	if c == nil || c.Values == nil {
+		return
+	}
+	keep := make(map[uint64]bool, len(active))
+	for _, id := range active {
+		keep[id] = true
+	}
+	for id := range c.Values {
+		if !keep[id] {
+			delete(c.Values, id)
+		}
+	}
+}
+
+// ParticipantCount returns the number of participants tracked by the clock
+func (c *Clock) ParticipantCount() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.Values)
+}
+
 // Compare compares two clocks (c vs other)
 func (c *Clock) Compare(other *Clock) int {
 	cIsNil := c == nil || c.Values == nil || len(c.Values) == 0
@@ -115,6 +145,33 @@ func (c *Clock) Compare(other *Clock) int {
 	return Equal
 }
 
+// Diff returns the per-participant delta of other relative to c (other minus c).
+// Participants with equal values are omitted. A participant present in only one
+// clock is always reported, with a delta of 0 if its value there is zero, so the
+// diff is empty exactly when Equal reports true.
+func (c *Clock) Diff(other *Clock) map[uint64]int64 {
+	diff := make(map[uint64]int64)
+	var cValues, otherValues map[uint64]uint64
+	if c != nil {
+		cValues = c.Values
+	}
+	if other != nil {
+		otherValues = other.Values
+	}
+	for id, otherValue := range otherValues {
+		cValue, exists := cValues[id]
+		if delta := int64(otherValue) - int64(cValue); delta != 0 || !exists {
+			diff[id] = delta
+		}
+	}
+	for id, cValue := range cValues {
+		if _, exists := otherValues[id]; !exists {
+			diff[id] = -int64(cValue)
+		}
+	}
+	return diff
+}
+
 // MarshalJSON implements JSON serialization
 func (c *Clock) MarshalJSON() ([]byte, error) {
 	if c == nil || c.Values == nil {
@@ -139,17 +196,37 @@ func (c *Clock) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &c.Values)
 }
 
-// Equals checks if two clocks are semantically equal
+// Equal reports whether two clocks track the same participants with the same values,
+// regardless of insertion order. It agrees with Compare, so a participant held at
+// zero is not equal to a missing one.
+func (c *Clock) Equal(other *Clock) bool {
+	return c.Compare(other) == Equal
+}
+
+// Equals checks if two clocks are semantically equal.
+//
+// Deprecated: use Equal.
 func (c *Clock) Equals(other *Clock) bool {
-	cIsNil := c == nil || c.Values == nil || len(c.Values) == 0
-	otherIsNil := other == nil || other.Values == nil || len(other.Values) == 0
-	if cIsNil {
-		return otherIsNil
+	return c.Equal(other)
+}
+
+// String renders the clock with participants in ascending order, e.g. {1:3,2:5},
+// so that log output is stable and diffable
+func (c *Clock) String() string {
+	if c == nil || len(c.Values) == 0 {
+		return "{}"
 	}
-	if otherIsNil {
-		return false
+	ids := make([]uint64, 0, len(c.Values))
+	for id := range c.Values {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%d:%d", id, c.Values[id]))
 	}
-	return reflect.DeepEqual(c.Values, other.Values)
+	return "{" + strings.Join(parts, ",") + "}"
 }
 
 // Copy creates a deep copy of the Clock and returns a POINTER to it
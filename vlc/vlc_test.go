@@ -0,0 +1,158 @@
+package vlc
+
+import (
+	"fmt"
+	"testing"
+)
+
+// clockOf builds a clock from participant/value pairs
+func clockOf(values map[uint64]uint64) *Clock {
+	c := New()
+	for id, value := range values {
+		c.Values[id] = value
+	}
+	return c
+}
+
+func TestPruneRemovesOnlyInactiveParticipants(t *testing.T) {
+	c := clockOf(map[uint64]uint64{1: 3, 2: 5, 3: 7, 4: 1})
+
+	c.Prune([]uint64{1, 3, 9})
+
+	if got := c.ParticipantCount(); got != 2 {
+		t.Fatalf("ParticipantCount() = %d, want 2", got)
+	}
+	if c.Values[1] != 3 || c.Values[3] != 7 {
+		t.Errorf("active entries changed: %v", c.Values)
+	}
+	if _, ok := c.Values[2]; ok {
+		t.Errorf("inactive participant 2 was kept: %v", c.Values)
+	}
+	if _, ok := c.Values[9]; ok {
+		t.Errorf("pruning added untracked participant 9: %v", c.Values)
+	}
+}
+
+func TestPruneEmptyActiveSetClearsClock(t *testing.T) {
+	c := clockOf(map[uint64]uint64{1: 3, 2: 5})
+	c.Prune(nil)
+	if got := c.ParticipantCount(); got != 0 {
+		t.Errorf("ParticipantCount() = %d, want 0", got)
+	}
+}
+
+func TestPruneNilClock(t *testing.T) {
+	var c *Clock
+	c.Prune([]uint64{1})
+	if got := c.ParticipantCount(); got != 0 {
+		t.Errorf("ParticipantCount() on nil clock = %d, want 0", got)
+	}
+}
+
+func TestComparePrunedAgainstUnpruned(t *testing.T) {
+	unpruned := clockOf(map[uint64]uint64{1: 3, 2: 5})
+	pruned := unpruned.Copy()
+	pruned.Prune([]uint64{1})
+
+	if got := pruned.Compare(unpruned); got != Less {
+		t.Errorf("pruned.Compare(unpruned) = %d, want Less", got)
+	}
+	if got := unpruned.Compare(pruned); got != Greater {
+		t.Errorf("unpruned.Compare(pruned) = %d, want Greater", got)
+	}
+
+	// Clocks pruned to the same set compare as if the dropped participant never existed
+	other := clockOf(map[uint64]uint64{1: 4, 2: 1})
+	other.Prune([]uint64{1})
+	if got := pruned.Compare(other); got != Less {
+		t.Errorf("pruned.Compare(other pruned) = %d, want Less", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		c, other *Clock
+		want     map[uint64]int64
+	}{
+		{"equal", clockOf(map[uint64]uint64{1: 2, 2: 3}), clockOf(map[uint64]uint64{1: 2, 2: 3}), map[uint64]int64{}},
+		{"ahead and behind", clockOf(map[uint64]uint64{1: 2, 2: 3}), clockOf(map[uint64]uint64{1: 4, 2: 1}), map[uint64]int64{1: 2, 2: -2}},
+		{"new participant", clockOf(map[uint64]uint64{1: 2}), clockOf(map[uint64]uint64{1: 2, 3: 5}), map[uint64]int64{3: 5}},
+		{"missing participant", clockOf(map[uint64]uint64{1: 2, 3: 5}), clockOf(map[uint64]uint64{1: 2}), map[uint64]int64{3: -5}},
+		{"explicit zero differs from missing", clockOf(map[uint64]uint64{1: 0}), New(), map[uint64]int64{1: 0}},
+		{"missing differs from explicit zero", clockOf(map[uint64]uint64{1: 2}), clockOf(map[uint64]uint64{1: 2, 2: 0}), map[uint64]int64{2: 0}},
+		{"nil receiver", nil, clockOf(map[uint64]uint64{1: 1}), map[uint64]int64{1: 1}},
+		{"nil other", clockOf(map[uint64]uint64{1: 1}), nil, map[uint64]int64{1: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.c.Diff(tt.other)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualAgreesWithCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		c, other *Clock
+		want     bool
+	}{
+		{"same values", clockOf(map[uint64]uint64{1: 2, 2: 3}), clockOf(map[uint64]uint64{1: 2, 2: 3}), true},
+		{"different value", clockOf(map[uint64]uint64{1: 2, 2: 3}), clockOf(map[uint64]uint64{1: 2, 2: 4}), false},
+		{"extra participant", clockOf(map[uint64]uint64{1: 2}), clockOf(map[uint64]uint64{1: 2, 2: 1}), false},
+		{"zero entry differs from missing", clockOf(map[uint64]uint64{1: 0}), New(), false},
+		{"zero entry differs from missing participant", clockOf(map[uint64]uint64{1: 2, 2: 0}), clockOf(map[uint64]uint64{1: 2}), false},
+		{"nil and empty", nil, New(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Equal(tt.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+			if got := tt.c.Equals(tt.other); got != tt.want {
+				t.Errorf("Equals() = %v, want %v", got, tt.want)
+			}
+			if got := len(tt.c.Diff(tt.other)) == 0; got != tt.want {
+				t.Errorf("empty Diff() is %v, want %v", got, tt.want)
+			}
+			if got := tt.c.Compare(tt.other) == Equal; got != tt.want {
+				t.Errorf("Compare() == Equal is %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualIgnoresInsertionOrder(t *testing.T) {
+	a, b := New(), New()
+	for _, id := range []uint64{1, 2, 3, 4, 5} {
+		a.Values[id] = id * 10
+	}
+	for _, id := range []uint64{5, 3, 1, 4, 2} {
+		b.Values[id] = id * 10
+	}
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("clocks built in different orders are not equal: %s vs %s", a, b)
+	}
+	if a.String() != b.String() {
+		t.Errorf("String() differs by insertion order: %s vs %s", a, b)
+	}
+}
+
+func TestStringIsSortedAndStable(t *testing.T) {
+	c := clockOf(map[uint64]uint64{10: 1, 2: 7, 1: 3})
+	want := "{1:3,2:7,10:1}"
+	for i := 0; i < 20; i++ {
+		if got := c.String(); got != want {
+			t.Fatalf("String() = %s, want %s", got, want)
+		}
+	}
+	if got := (*Clock)(nil).String(); got != "{}" {
+		t.Errorf("nil String() = %s, want {}", got)
+	}
+}